@@ -0,0 +1,96 @@
+package affine
+
+import (
+  "crypto/cipher"
+  "fmt"
+
+  "github.com/timebertt/grypto/euclid"
+)
+
+const (
+  BlockSize = 1
+
+  minUpper, maxUpper = 'A', 'Z'
+  minLower, maxLower = 'a', 'z'
+
+  modulus = 26
+)
+
+type affine struct {
+  a, b int
+  // aInv is the multiplicative inverse of a modulo 26, used for decryption
+  aInv int
+}
+
+// NewCipher returns a new cipher.Block which implements the Affine Cipher.
+// It is a substituting block cipher operating on blocks of length 1 (single bytes) and a generalization of the
+// Caesar Cipher. Latin characters are encrypted by mapping their position x in the alphabet to the character at
+// position a*x + b mod 26. The characters' case is kept and non-latin characters are not replaced.
+// Decryption maps x back to a⁻¹*(x - b) mod 26, which requires a to be invertible modulo 26, i.e. a must be coprime
+// to 26 (gcd(a, 26) = 1). Otherwise, multiple characters would be mapped to the same character and an error is
+// returned. With a = 1, the Affine Cipher is the same as the Caesar Cipher with key b.
+// See https://en.wikipedia.org/wiki/Affine_cipher
+func NewCipher(a, b int) (cipher.Block, error) {
+  // normalize keys, modulo operation does not return a positive residue for a negative number
+  a, b = a%modulus, b%modulus
+  if a < 0 {
+    a += modulus
+  }
+  if b < 0 {
+    b += modulus
+  }
+
+  if gcd := euclid.GreatestCommonDivisor(a, modulus); gcd != 1 {
+    return nil, fmt.Errorf("grypto/affine: key a=%d is not coprime to %d (gcd is %d)", a, modulus, gcd)
+  }
+
+  // gcd(26, a) = 1 = x*26 + y*a => y is a's multiplicative inverse modulo 26
+  _, _, aInv := euclid.GreatestCommonDivisorExtended(modulus, a)
+  if aInv < 0 {
+    aInv += modulus
+  }
+
+  return affine{a: a, b: b, aInv: aInv}, nil
+}
+
+func (c affine) BlockSize() int {
+  return BlockSize
+}
+
+func (c affine) Encrypt(dst, src []byte) {
+  cryptBlock(dst, src, func(x int) int {
+    return c.a*x + c.b
+  })
+}
+
+func (c affine) Decrypt(dst, src []byte) {
+  cryptBlock(dst, src, func(x int) int {
+    // add modulus to get a positive residue
+    return c.aInv * (x - c.b + modulus)
+  })
+}
+
+func cryptBlock(dst, src []byte, f func(x int) int) {
+  if len(src) < BlockSize {
+    panic("grypto/affine: input not full block")
+  }
+  if len(dst) < BlockSize {
+    panic("grypto/affine: output not full block")
+  }
+
+  // convert to rune for easy comparing
+  in := rune(src[0])
+
+  switch {
+  case in >= minUpper && in <= maxUpper:
+    dst[0] = byte(substitute(f, in, minUpper))
+  case in >= minLower && in <= maxLower:
+    dst[0] = byte(substitute(f, in, minLower))
+  default:
+    dst[0] = src[0]
+  }
+}
+
+func substitute(f func(x int) int, in, min rune) rune {
+  return min + rune(f(int(in-min))%modulus)
+}
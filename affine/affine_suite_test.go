@@ -0,0 +1,13 @@
+package affine_test
+
+import (
+  "testing"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+)
+
+func TestAffine(t *testing.T) {
+  RegisterFailHandler(Fail)
+  RunSpecs(t, "Affine Cipher Suite")
+}
@@ -0,0 +1,77 @@
+package affine_test
+
+import (
+  "crypto/cipher"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/affine"
+  "github.com/timebertt/grypto/block"
+  "github.com/timebertt/grypto/caesar"
+)
+
+var _ = Describe("Affine Cipher", func() {
+  crypt := func(mode cipher.BlockMode, in string) string {
+    out := make([]byte, len(in))
+    mode.CryptBlocks(out, []byte(in))
+    return string(out)
+  }
+
+  newCipher := func(a, b int) cipher.Block {
+    c, err := affine.NewCipher(a, b)
+    ExpectWithOffset(1, err).NotTo(HaveOccurred())
+    return c
+  }
+
+  It("should reject keys not coprime to 26", func() {
+    test := func(a int) {
+      _, err := affine.NewCipher(a, 1)
+      ExpectWithOffset(1, err).To(HaveOccurred())
+    }
+
+    test(0)
+    test(2)
+    test(13)
+    test(26)
+    test(-4)
+  })
+
+  It("should encrypt and decrypt correctly", func() {
+    c := newCipher(5, 8)
+    Expect(crypt(block.NewECBEncrypter(c), "AFFINE CIPHER")).To(Equal("IHHWVC SWFRCP"))
+    Expect(crypt(block.NewECBDecrypter(c), "IHHWVC SWFRCP")).To(Equal("AFFINE CIPHER"))
+  })
+
+  It("should keep case and not encrypt non-latin letters", func() {
+    c := newCipher(5, 8)
+    Expect(crypt(block.NewECBEncrypter(c), "Affine cipher!")).To(Equal("Ihhwvc swfrcp!"))
+    Expect(crypt(block.NewECBDecrypter(c), "Ihhwvc swfrcp!")).To(Equal("Affine cipher!"))
+  })
+
+  It("should normalize keys", func() {
+    in := "The quick brown fox jumps over the lazy dog"
+    expected := crypt(block.NewECBEncrypter(newCipher(5, 8)), in)
+
+    Expect(crypt(block.NewECBEncrypter(newCipher(31, 34)), in)).To(Equal(expected))
+    Expect(crypt(block.NewECBEncrypter(newCipher(-21, -18)), in)).To(Equal(expected))
+  })
+
+  It("should round-trip all valid keys", func() {
+    in := "The quick brown fox jumps over the lazy dog"
+    for _, a := range []int{1, 3, 5, 7, 9, 11, 15, 17, 19, 21, 23, 25} {
+      for b := 0; b < 26; b++ {
+        c := newCipher(a, b)
+        Expect(crypt(block.NewECBDecrypter(c), crypt(block.NewECBEncrypter(c), in))).To(Equal(in))
+      }
+    }
+  })
+
+  It("should be the same as the Caesar Cipher for a=1", func() {
+    in := "Caesar Cipher is old but not very secure"
+    for b := 0; b < 26; b++ {
+      expected := crypt(block.NewECBEncrypter(caesar.NewCipher(b)), in)
+      Expect(crypt(block.NewECBEncrypter(newCipher(1, b)), in)).To(Equal(expected))
+    }
+  })
+})
@@ -0,0 +1,51 @@
+package block_test
+
+import (
+  "crypto/aes"
+  "crypto/cipher"
+  "math/rand"
+  "testing"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+)
+
+func TestBlock(t *testing.T) {
+  RegisterFailHandler(Fail)
+  RunSpecs(t, "Block Modes Suite")
+}
+
+var (
+  aesKey = []byte("0123456789abcdef")
+  aesIV  = []byte("fedcba9876543210")
+)
+
+func newAES() cipher.Block {
+  block, err := aes.NewCipher(aesKey)
+  ExpectWithOffset(1, err).NotTo(HaveOccurred())
+  return block
+}
+
+// randomBytes returns n pseudo-random bytes (deterministic for reproducible test runs).
+func randomBytes(n int) []byte {
+  b := make([]byte, n)
+  rand.New(rand.NewSource(int64(n))).Read(b)
+  return b
+}
+
+// testStream XORs src with the key streams of actual and expected in chunks of the given size and expects equal
+// output.
+func testStream(actual, expected cipher.Stream, src []byte, chunkSize int) {
+  dst, dstExpected := make([]byte, len(src)), make([]byte, len(src))
+
+  for i := 0; i < len(src); i += chunkSize {
+    end := i + chunkSize
+    if end > len(src) {
+      end = len(src)
+    }
+    actual.XORKeyStream(dst[i:end], src[i:end])
+    expected.XORKeyStream(dstExpected[i:end], src[i:end])
+  }
+
+  ExpectWithOffset(1, dst).To(Equal(dstExpected))
+}
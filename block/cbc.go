@@ -0,0 +1,101 @@
+package block
+
+import "crypto/cipher"
+
+type cbc struct {
+  block     cipher.Block
+  blockSize int
+  iv        []byte
+  tmp       []byte
+}
+
+func newCBC(block cipher.Block, iv []byte) *cbc {
+  blockSize := block.BlockSize()
+  if len(iv) != blockSize {
+    panic("grypto/cbc: IV length must equal block size")
+  }
+
+  return &cbc{
+    block:     block,
+    blockSize: blockSize,
+    iv:        append([]byte(nil), iv...),
+    tmp:       make([]byte, blockSize),
+  }
+}
+
+// NewCBCEncrypter returns a new cipher.BlockMode which uses the given Block cipher to encrypt given blocks in
+// cipher block chaining mode (CBC). In contrast to ECB, each plaintext block is XORed with the previous ciphertext
+// block before being encrypted. The first block is XORed with the given initialization vector (IV), which must
+// have the same length as the Block's block size. This way, identical plaintext blocks are encrypted to different
+// ciphertext blocks and patterns in the plaintext are hidden.
+// The IV doesn't need to be secret, but it must be unpredictable and should never be reused with the same key.
+// See https://en.wikipedia.org/wiki/Block_cipher_mode_of_operation#Cipher_block_chaining_(CBC)
+func NewCBCEncrypter(block cipher.Block, iv []byte) cipher.BlockMode {
+  return (*cbcEncrypter)(newCBC(block, iv))
+}
+
+type cbcEncrypter cbc
+
+func (c *cbcEncrypter) BlockSize() int {
+  return c.blockSize
+}
+
+func (c *cbcEncrypter) CryptBlocks(dst, src []byte) {
+  if len(src)%c.blockSize != 0 {
+    panic("grypto/cbc: input not full blocks")
+  }
+  if len(dst) < len(src) {
+    panic("grypto/cbc: output smaller than input")
+  }
+
+  for len(src) > 0 {
+    // chain with previous ciphertext block (or IV) and encrypt
+    xorBytes(dst[:c.blockSize], src[:c.blockSize], c.iv)
+    c.block.Encrypt(dst[:c.blockSize], dst[:c.blockSize])
+
+    // remember ciphertext block for chaining the next block
+    copy(c.iv, dst[:c.blockSize])
+
+    // move to the next block
+    src = src[c.blockSize:]
+    dst = dst[c.blockSize:]
+  }
+}
+
+// NewCBCDecrypter returns a new cipher.BlockMode which uses the given Block cipher to decrypt given blocks in
+// cipher block chaining mode (CBC). Each ciphertext block is decrypted and XORed with the previous ciphertext block
+// (or the initialization vector for the first block). The given IV must be the same IV that was used for encryption.
+// See NewCBCEncrypter.
+func NewCBCDecrypter(block cipher.Block, iv []byte) cipher.BlockMode {
+  return (*cbcDecrypter)(newCBC(block, iv))
+}
+
+type cbcDecrypter cbc
+
+func (c *cbcDecrypter) BlockSize() int {
+  return c.blockSize
+}
+
+func (c *cbcDecrypter) CryptBlocks(dst, src []byte) {
+  if len(src)%c.blockSize != 0 {
+    panic("grypto/cbc: input not full blocks")
+  }
+  if len(dst) < len(src) {
+    panic("grypto/cbc: output smaller than input")
+  }
+
+  for len(src) > 0 {
+    // save ciphertext block before decrypting, dst and src might overlap
+    copy(c.tmp, src[:c.blockSize])
+
+    c.block.Decrypt(dst[:c.blockSize], src[:c.blockSize])
+    xorBytes(dst[:c.blockSize], dst[:c.blockSize], c.iv)
+
+    // ciphertext block is used for chaining the next block
+    c.iv, c.tmp = c.tmp, c.iv
+
+    // move to the next block
+    src = src[c.blockSize:]
+    dst = dst[c.blockSize:]
+  }
+}
@@ -0,0 +1,85 @@
+package block_test
+
+import (
+  "crypto/cipher"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/block"
+  "github.com/timebertt/grypto/caesar"
+)
+
+var _ = Describe("CBC", func() {
+  It("should panic on invalid IV length", func() {
+    Expect(func() {
+      block.NewCBCEncrypter(newAES(), aesIV[:8])
+    }).To(Panic())
+    Expect(func() {
+      block.NewCBCDecrypter(newAES(), aesIV[:8])
+    }).To(Panic())
+  })
+
+  It("should panic on input not full blocks", func() {
+    Expect(func() {
+      block.NewCBCEncrypter(newAES(), aesIV).CryptBlocks(make([]byte, 17), make([]byte, 17))
+    }).To(Panic())
+    Expect(func() {
+      block.NewCBCDecrypter(newAES(), aesIV).CryptBlocks(make([]byte, 17), make([]byte, 17))
+    }).To(Panic())
+  })
+
+  It("should produce the same output as crypto/cipher", func() {
+    test := func(n int) {
+      src := randomBytes(n)
+      dst, expected := make([]byte, n), make([]byte, n)
+
+      block.NewCBCEncrypter(newAES(), aesIV).CryptBlocks(dst, src)
+      cipher.NewCBCEncrypter(newAES(), aesIV).CryptBlocks(expected, src)
+      ExpectWithOffset(1, dst).To(Equal(expected), "ciphertext should be equal")
+
+      block.NewCBCDecrypter(newAES(), aesIV).CryptBlocks(dst, expected)
+      ExpectWithOffset(1, dst).To(Equal(src), "plaintext should be equal")
+    }
+
+    test(0)
+    test(16)
+    test(32)
+    test(160)
+    test(4096)
+  })
+
+  It("should keep chaining across multiple calls", func() {
+    src := randomBytes(64)
+    dst, expected := make([]byte, 64), make([]byte, 64)
+
+    enc := block.NewCBCEncrypter(newAES(), aesIV)
+    enc.CryptBlocks(dst[:16], src[:16])
+    enc.CryptBlocks(dst[16:], src[16:])
+    cipher.NewCBCEncrypter(newAES(), aesIV).CryptBlocks(expected, src)
+    Expect(dst).To(Equal(expected))
+
+    dec := block.NewCBCDecrypter(newAES(), aesIV)
+    dec.CryptBlocks(dst[:32], dst[:32])
+    dec.CryptBlocks(dst[32:], dst[32:])
+    Expect(dst).To(Equal(src))
+  })
+
+  It("should hide repeating plaintext blocks", func() {
+    src := []byte("AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA")
+    dst := make([]byte, len(src))
+
+    block.NewCBCEncrypter(newAES(), aesIV).CryptBlocks(dst, src)
+    Expect(dst[:16]).NotTo(Equal(dst[16:]))
+  })
+
+  It("should work with the caesar cipher", func() {
+    src := []byte("Caesar Cipher in CBC mode")
+    dst := make([]byte, len(src))
+
+    block.NewCBCEncrypter(caesar.NewCipher(3), []byte{0}).CryptBlocks(dst, src)
+    Expect(dst).NotTo(Equal(src))
+    block.NewCBCDecrypter(caesar.NewCipher(3), []byte{0}).CryptBlocks(dst, dst)
+    Expect(dst).To(Equal(src))
+  })
+})
@@ -0,0 +1,71 @@
+package block
+
+import "crypto/cipher"
+
+type cfb struct {
+  block cipher.Block
+  // register holds the previous ciphertext block, which is encrypted to generate the next key stream block
+  register  []byte
+  keyStream []byte
+  // used is the number of bytes of keyStream that have already been used
+  used    int
+  decrypt bool
+}
+
+func newCFB(block cipher.Block, iv []byte, decrypt bool) *cfb {
+  blockSize := block.BlockSize()
+  if len(iv) != blockSize {
+    panic("grypto/cfb: IV length must equal block size")
+  }
+
+  return &cfb{
+    block:     block,
+    register:  append([]byte(nil), iv...),
+    keyStream: make([]byte, blockSize),
+    used:      blockSize,
+    decrypt:   decrypt,
+  }
+}
+
+// NewCFBEncrypter returns a new cipher.Stream which uses the given Block cipher to encrypt in cipher feedback mode
+// (CFB). CFB turns a block cipher into a self-synchronizing stream cipher: the previous ciphertext block (or the
+// initialization vector for the first block) is encrypted to generate a key stream block, which is then XORed with
+// the plaintext. The given IV must have the same length as the Block's block size.
+// As the key stream depends on the ciphertext, it cannot be precomputed, but the input doesn't need to be padded.
+// See https://en.wikipedia.org/wiki/Block_cipher_mode_of_operation#Cipher_feedback_(CFB)
+func NewCFBEncrypter(block cipher.Block, iv []byte) cipher.Stream {
+  return newCFB(block, iv, false)
+}
+
+// NewCFBDecrypter returns a new cipher.Stream which uses the given Block cipher to decrypt in cipher feedback mode
+// (CFB). The given IV must be the same IV that was used for encryption. See NewCFBEncrypter.
+func NewCFBDecrypter(block cipher.Block, iv []byte) cipher.Stream {
+  return newCFB(block, iv, true)
+}
+
+func (c *cfb) XORKeyStream(dst, src []byte) {
+  if len(dst) < len(src) {
+    panic("grypto/cfb: output smaller than input")
+  }
+
+  for len(src) > 0 {
+    if c.used == len(c.keyStream) {
+      // generate next key stream block from the previous ciphertext block
+      c.block.Encrypt(c.keyStream, c.register)
+      c.used = 0
+    }
+
+    if c.decrypt {
+      // save ciphertext before XORing, dst and src might overlap
+      copy(c.register[c.used:], src)
+    }
+    n := xorBytes(dst, src, c.keyStream[c.used:])
+    if !c.decrypt {
+      copy(c.register[c.used:], dst[:n])
+    }
+
+    c.used += n
+    src = src[n:]
+    dst = dst[n:]
+  }
+}
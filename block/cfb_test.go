@@ -0,0 +1,59 @@
+package block_test
+
+import (
+  "crypto/cipher"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/block"
+  "github.com/timebertt/grypto/caesar"
+)
+
+var _ = Describe("CFB", func() {
+  It("should panic on invalid IV length", func() {
+    Expect(func() {
+      block.NewCFBEncrypter(newAES(), aesIV[:8])
+    }).To(Panic())
+    Expect(func() {
+      block.NewCFBDecrypter(newAES(), aesIV[:8])
+    }).To(Panic())
+  })
+
+  It("should produce the same output as crypto/cipher", func() {
+    test := func(n, chunkSize int) {
+      src := randomBytes(n)
+      ciphertext := make([]byte, n)
+      cipher.NewCFBEncrypter(newAES(), aesIV).XORKeyStream(ciphertext, src)
+
+      testStream(block.NewCFBEncrypter(newAES(), aesIV), cipher.NewCFBEncrypter(newAES(), aesIV), src, chunkSize)
+      testStream(block.NewCFBDecrypter(newAES(), aesIV), cipher.NewCFBDecrypter(newAES(), aesIV), ciphertext, chunkSize)
+    }
+
+    test(1, 1)
+    test(15, 4)
+    test(16, 16)
+    test(100, 7)
+    test(4096, 1000)
+  })
+
+  It("should decrypt in place", func() {
+    src := randomBytes(100)
+    buf := append([]byte(nil), src...)
+
+    block.NewCFBEncrypter(newAES(), aesIV).XORKeyStream(buf, buf)
+    Expect(buf).NotTo(Equal(src))
+    block.NewCFBDecrypter(newAES(), aesIV).XORKeyStream(buf, buf)
+    Expect(buf).To(Equal(src))
+  })
+
+  It("should work with the caesar cipher", func() {
+    src := []byte("Caesar Cipher in CFB mode")
+    dst := make([]byte, len(src))
+
+    block.NewCFBEncrypter(caesar.NewCipher(3), []byte{'x'}).XORKeyStream(dst, src)
+    Expect(dst).NotTo(Equal(src))
+    block.NewCFBDecrypter(caesar.NewCipher(3), []byte{'x'}).XORKeyStream(dst, dst)
+    Expect(dst).To(Equal(src))
+  })
+})
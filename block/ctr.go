@@ -0,0 +1,64 @@
+package block
+
+import "crypto/cipher"
+
+type ctr struct {
+  block     cipher.Block
+  counter   []byte
+  keyStream []byte
+  // used is the number of bytes of keyStream that have already been used
+  used int
+}
+
+// NewCTR returns a new cipher.Stream which uses the given Block cipher to encrypt or decrypt in counter mode (CTR).
+// CTR turns a block cipher into a synchronous stream cipher: a counter block is encrypted to generate the key
+// stream, which is then XORed with the input. The counter starts at the given initialization vector and is
+// incremented (as a big-endian integer) for every block. The given IV must have the same length as the Block's
+// block size.
+// Like in OFB, encryption and decryption are the exact same operation. Additionally, each key stream block can be
+// computed independently of the others, which allows for parallel processing and random access.
+// See https://en.wikipedia.org/wiki/Block_cipher_mode_of_operation#Counter_(CTR)
+func NewCTR(block cipher.Block, iv []byte) cipher.Stream {
+  blockSize := block.BlockSize()
+  if len(iv) != blockSize {
+    panic("grypto/ctr: IV length must equal block size")
+  }
+
+  return &ctr{
+    block:     block,
+    counter:   append([]byte(nil), iv...),
+    keyStream: make([]byte, blockSize),
+    used:      blockSize,
+  }
+}
+
+func (c *ctr) XORKeyStream(dst, src []byte) {
+  if len(dst) < len(src) {
+    panic("grypto/ctr: output smaller than input")
+  }
+
+  for len(src) > 0 {
+    if c.used == len(c.keyStream) {
+      // generate next key stream block from the counter
+      c.block.Encrypt(c.keyStream, c.counter)
+      c.increment()
+      c.used = 0
+    }
+
+    n := xorBytes(dst, src, c.keyStream[c.used:])
+
+    c.used += n
+    src = src[n:]
+    dst = dst[n:]
+  }
+}
+
+// increment increments the counter block as a big-endian integer (wrapping around on overflow).
+func (c *ctr) increment() {
+  for i := len(c.counter) - 1; i >= 0; i-- {
+    c.counter[i]++
+    if c.counter[i] != 0 {
+      return
+    }
+  }
+}
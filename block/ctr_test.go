@@ -0,0 +1,49 @@
+package block_test
+
+import (
+  "crypto/cipher"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/block"
+  "github.com/timebertt/grypto/caesar"
+)
+
+var _ = Describe("CTR", func() {
+  It("should panic on invalid IV length", func() {
+    Expect(func() {
+      block.NewCTR(newAES(), aesIV[:8])
+    }).To(Panic())
+  })
+
+  It("should produce the same output as crypto/cipher", func() {
+    test := func(n, chunkSize int) {
+      testStream(block.NewCTR(newAES(), aesIV), cipher.NewCTR(newAES(), aesIV), randomBytes(n), chunkSize)
+    }
+
+    test(1, 1)
+    test(15, 4)
+    test(16, 16)
+    test(100, 7)
+    test(4096, 1000)
+  })
+
+  It("should carry counter overflows into the next byte", func() {
+    iv := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 255, 255, 255, 254}
+    testStream(block.NewCTR(newAES(), iv), cipher.NewCTR(newAES(), iv), randomBytes(64), 16)
+
+    iv = []byte{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255}
+    testStream(block.NewCTR(newAES(), iv), cipher.NewCTR(newAES(), iv), randomBytes(64), 16)
+  })
+
+  It("should work with the caesar cipher", func() {
+    src := []byte("Caesar Cipher in CTR mode")
+    dst := make([]byte, len(src))
+
+    block.NewCTR(caesar.NewCipher(3), []byte{'a'}).XORKeyStream(dst, src)
+    Expect(dst).NotTo(Equal(src))
+    block.NewCTR(caesar.NewCipher(3), []byte{'a'}).XORKeyStream(dst, dst)
+    Expect(dst).To(Equal(src))
+  })
+})
@@ -0,0 +1,188 @@
+package block
+
+import "crypto/cipher"
+
+// CTSVariant selects the order of the last two ciphertext blocks in ciphertext stealing mode as defined in the
+// addendum to NIST SP 800-38A.
+type CTSVariant int
+
+const (
+  // CS1 keeps the order of the last two blocks: the partial block C*ₙ₋₁ is followed by the full block Cₙ.
+  CS1 CTSVariant = iota + 1
+  // CS2 swaps the last two blocks only if the input is not a multiple of the block size, so that the ciphertext of
+  // block-aligned input is the same as without ciphertext stealing.
+  CS2
+  // CS3 unconditionally swaps the last two blocks (also known as the Kerberos variant, see RFC 3962).
+  CS3
+)
+
+type cts struct {
+  blockSize int
+  // mode is the underlying ECB or CBC mode used for all blocks except the last one
+  mode cipher.BlockMode
+  // raw is an ECB decrypter used for decrypting the last block without chaining
+  raw     cipher.BlockMode
+  cbc     bool
+  variant CTSVariant
+}
+
+func newCTS(mode, raw cipher.BlockMode, cbc bool, variant CTSVariant) *cts {
+  if variant < CS1 || variant > CS3 {
+    panic("grypto/cts: invalid variant")
+  }
+
+  return &cts{
+    blockSize: mode.BlockSize(),
+    mode:      mode,
+    raw:       raw,
+    cbc:       cbc,
+    variant:   variant,
+  }
+}
+
+// NewCBCCTSEncrypter returns a new cipher.BlockMode which uses the given Block cipher to encrypt in cipher block
+// chaining mode with ciphertext stealing (CBC-CTS). Ciphertext stealing allows to encrypt input of any length of at
+// least one block without padding, so that the ciphertext has exactly the same length as the plaintext.
+// The last incomplete plaintext block is padded with zeros and encrypted like in CBC mode. Because the last block is
+// chained with the previous ciphertext block, the ciphertext block before it doesn't need to be transmitted in full:
+// it is truncated to the length of the last plaintext block (its tail is "stolen").
+// In contrast to the other block modes, CryptBlocks accepts input that is not a multiple of the block size, but it
+// must be called with the complete message at once, as the last two blocks are treated specially.
+// See https://en.wikipedia.org/wiki/Ciphertext_stealing
+func NewCBCCTSEncrypter(block cipher.Block, iv []byte, variant CTSVariant) cipher.BlockMode {
+  return (*ctsEncrypter)(newCTS(NewCBCEncrypter(block, iv), nil, true, variant))
+}
+
+// NewCBCCTSDecrypter returns a new cipher.BlockMode which uses the given Block cipher to decrypt in cipher block
+// chaining mode with ciphertext stealing (CBC-CTS). See NewCBCCTSEncrypter.
+func NewCBCCTSDecrypter(block cipher.Block, iv []byte, variant CTSVariant) cipher.BlockMode {
+  return (*ctsDecrypter)(newCTS(NewCBCDecrypter(block, iv), NewECBDecrypter(block), true, variant))
+}
+
+// NewECBCTSEncrypter returns a new cipher.BlockMode which uses the given Block cipher to encrypt in electronic code
+// book mode with ciphertext stealing (ECB-CTS). Instead of zeros, the last incomplete plaintext block is padded with
+// the tail of the previous ciphertext block, which is then truncated to the length of the last plaintext block.
+// Like NewCBCCTSEncrypter, CryptBlocks accepts input of any length of at least one block, but it must be called with
+// the complete message at once.
+// See https://en.wikipedia.org/wiki/Ciphertext_stealing
+func NewECBCTSEncrypter(block cipher.Block, variant CTSVariant) cipher.BlockMode {
+  return (*ctsEncrypter)(newCTS(NewECBEncrypter(block), nil, false, variant))
+}
+
+// NewECBCTSDecrypter returns a new cipher.BlockMode which uses the given Block cipher to decrypt in electronic code
+// book mode with ciphertext stealing (ECB-CTS). See NewECBCTSEncrypter.
+func NewECBCTSDecrypter(block cipher.Block, variant CTSVariant) cipher.BlockMode {
+  return (*ctsDecrypter)(newCTS(NewECBDecrypter(block), NewECBDecrypter(block), false, variant))
+}
+
+type ctsEncrypter cts
+
+func (c *ctsEncrypter) BlockSize() int {
+  return c.blockSize
+}
+
+func (c *ctsEncrypter) CryptBlocks(dst, src []byte) {
+  if len(src) < c.blockSize {
+    panic("grypto/cts: input smaller than block size")
+  }
+  if len(dst) < len(src) {
+    panic("grypto/cts: output smaller than input")
+  }
+
+  n, d := len(src), len(src)%c.blockSize
+  if d == 0 {
+    c.mode.CryptBlocks(dst[:n], src)
+    if c.variant == CS3 && n > c.blockSize {
+      swapLastBlocks(dst[:n], c.blockSize)
+    }
+    return
+  }
+
+  // encrypt all full blocks, C₁ ... Cₙ₋₁
+  full := n - d
+  c.mode.CryptBlocks(dst[:full], src[:full])
+  prev := dst[full-c.blockSize : full]
+
+  // pad the last plaintext block with zeros (CBC) or the tail of the previous ciphertext block (ECB) and encrypt it
+  last := make([]byte, c.blockSize)
+  copy(last, src[full:])
+  if !c.cbc {
+    copy(last[d:], prev[d:])
+  }
+  c.mode.CryptBlocks(last, last)
+
+  // C*ₙ₋₁ (the first d bytes of Cₙ₋₁) is already in place for CS1
+  if c.variant == CS1 {
+    copy(dst[full-c.blockSize+d:], last)
+    return
+  }
+
+  // Cₙ || C*ₙ₋₁ for CS2 and CS3
+  stolen := append([]byte(nil), prev[:d]...)
+  copy(dst[full-c.blockSize:], last)
+  copy(dst[full:], stolen)
+}
+
+type ctsDecrypter cts
+
+func (c *ctsDecrypter) BlockSize() int {
+  return c.blockSize
+}
+
+func (c *ctsDecrypter) CryptBlocks(dst, src []byte) {
+  if len(src) < c.blockSize {
+    panic("grypto/cts: input smaller than block size")
+  }
+  if len(dst) < len(src) {
+    panic("grypto/cts: output smaller than input")
+  }
+
+  n, d := len(src), len(src)%c.blockSize
+  if d == 0 {
+    if c.variant != CS3 || n == c.blockSize {
+      c.mode.CryptBlocks(dst[:n], src)
+      return
+    }
+
+    // restore the original order of the last two blocks before decrypting them
+    c.mode.CryptBlocks(dst[:n-2*c.blockSize], src[:n-2*c.blockSize])
+    last := append([]byte(nil), src[n-2*c.blockSize:]...)
+    swapLastBlocks(last, c.blockSize)
+    c.mode.CryptBlocks(dst[n-2*c.blockSize:n], last)
+    return
+  }
+
+  // extract C*ₙ₋₁ and Cₙ, dst and src might overlap
+  full := n - d
+  var stolen, last []byte
+  if c.variant == CS1 {
+    stolen = append(stolen, src[full-c.blockSize:full-c.blockSize+d]...)
+    last = append(last, src[full-c.blockSize+d:n]...)
+  } else {
+    last = append(last, src[full-c.blockSize:full]...)
+    stolen = append(stolen, src[full:n]...)
+  }
+
+  // decrypt C₁ ... Cₙ₋₂
+  c.mode.CryptBlocks(dst[:full-c.blockSize], src[:full-c.blockSize])
+
+  // decrypting Cₙ without chaining yields the zero padded last plaintext block XORed with Cₙ₋₁ (CBC) or the last
+  // plaintext block padded with the tail of Cₙ₋₁ (ECB)
+  c.raw.CryptBlocks(last, last)
+  prev := append(stolen, last[d:]...)
+  if c.cbc {
+    xorBytes(last[:d], last[:d], stolen)
+  }
+
+  // decrypt the restored Cₙ₋₁
+  c.mode.CryptBlocks(dst[full-c.blockSize:full], prev)
+  copy(dst[full:n], last[:d])
+}
+
+// swapLastBlocks swaps the last two blocks of p.
+func swapLastBlocks(p []byte, blockSize int) {
+  a, b := p[len(p)-2*blockSize:len(p)-blockSize], p[len(p)-blockSize:]
+  for i := range a {
+    a[i], b[i] = b[i], a[i]
+  }
+}
@@ -0,0 +1,173 @@
+package block_test
+
+import (
+  "crypto/aes"
+  "crypto/cipher"
+  "encoding/hex"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/block"
+)
+
+var _ = Describe("CTS", func() {
+  variants := []block.CTSVariant{block.CS1, block.CS2, block.CS3}
+
+  It("should panic on invalid inputs", func() {
+    Expect(func() {
+      block.NewCBCCTSEncrypter(newAES(), aesIV, 0)
+    }).To(Panic())
+    Expect(func() {
+      block.NewCBCCTSEncrypter(newAES(), aesIV, block.CS1).CryptBlocks(make([]byte, 15), make([]byte, 15))
+    }).To(Panic())
+    Expect(func() {
+      block.NewECBCTSDecrypter(newAES(), block.CS1).CryptBlocks(make([]byte, 15), make([]byte, 15))
+    }).To(Panic())
+  })
+
+  Context("CBC", func() {
+    // test vectors from RFC 3962, Appendix B (CBC-CS3 with AES-128 and zero IV)
+    var (
+      key = []byte("chicken teriyaki")
+      iv  = make([]byte, aes.BlockSize)
+
+      vectors = []struct{ plaintext, ciphertext string }{
+        {
+          "4920776f756c64206c696b652074686520",
+          "c6353568f2bf8cb4d8a580362da7ff7f97",
+        },
+        {
+          "4920776f756c64206c696b65207468652047656e6572616c20476175277320",
+          "fc00783e0efdb2c1d445d4c8eff7ed2297687268d6ecccc0c07b25e25ecfe5",
+        },
+        {
+          "4920776f756c64206c696b65207468652047656e6572616c2047617527732043",
+          "39312523a78662d5be7fcbcc98ebf5a897687268d6ecccc0c07b25e25ecfe584",
+        },
+        {
+          "4920776f756c64206c696b65207468652047656e6572616c20476175277320436869636b656e2c20706c656173652c",
+          "97687268d6ecccc0c07b25e25ecfe584b3fffd940c16a18c1b5549d2f838029e39312523a78662d5be7fcbcc98ebf5",
+        },
+        {
+          "4920776f756c64206c696b65207468652047656e6572616c20476175277320436869636b656e2c20706c656173652c20",
+          "97687268d6ecccc0c07b25e25ecfe5849dad8bbb96c4cdc03bc103e1a194bbd839312523a78662d5be7fcbcc98ebf5a8",
+        },
+        {
+          "4920776f756c64206c696b65207468652047656e6572616c20476175277320436869636b656e2c20706c656173652c20" +
+            "616e6420776f6e746f6e20736f75702e",
+          "97687268d6ecccc0c07b25e25ecfe58439312523a78662d5be7fcbcc98ebf5a84807efe836ee89a526730dbc2f7bc840" +
+            "9dad8bbb96c4cdc03bc103e1a194bbd8",
+        },
+      }
+    )
+
+    crypt := func(mode cipher.BlockMode, src []byte) []byte {
+      dst := make([]byte, len(src))
+      mode.CryptBlocks(dst, src)
+      return dst
+    }
+
+    It("should match the test vectors for CS3", func() {
+      b, err := aes.NewCipher(key)
+      Expect(err).NotTo(HaveOccurred())
+
+      for _, v := range vectors {
+        plaintext, _ := hex.DecodeString(v.plaintext)
+        ciphertext, _ := hex.DecodeString(v.ciphertext)
+
+        Expect(crypt(block.NewCBCCTSEncrypter(b, iv, block.CS3), plaintext)).To(Equal(ciphertext))
+        Expect(crypt(block.NewCBCCTSDecrypter(b, iv, block.CS3), ciphertext)).To(Equal(plaintext))
+      }
+    })
+
+    It("should order the last blocks correctly for CS1 and CS2", func() {
+      b, err := aes.NewCipher(key)
+      Expect(err).NotTo(HaveOccurred())
+
+      for _, v := range vectors {
+        plaintext, _ := hex.DecodeString(v.plaintext)
+        ciphertext, _ := hex.DecodeString(v.ciphertext)
+
+        n, d := len(plaintext), len(plaintext)%aes.BlockSize
+        if d == 0 {
+          // CS1 and CS2 don't swap aligned input, which is the same as plain CBC
+          expected := crypt(cipher.NewCBCEncrypter(b, iv), plaintext)
+          Expect(crypt(block.NewCBCCTSEncrypter(b, iv, block.CS1), plaintext)).To(Equal(expected))
+          Expect(crypt(block.NewCBCCTSEncrypter(b, iv, block.CS2), plaintext)).To(Equal(expected))
+          continue
+        }
+
+        // CS2 is the same as CS3 for unaligned input
+        Expect(crypt(block.NewCBCCTSEncrypter(b, iv, block.CS2), plaintext)).To(Equal(ciphertext))
+
+        // CS1 has the last two blocks in the original order: C*ₙ₋₁ || Cₙ
+        full := n - d
+        expected := append([]byte(nil), ciphertext[:full-aes.BlockSize]...)
+        expected = append(expected, ciphertext[full:]...)
+        expected = append(expected, ciphertext[full-aes.BlockSize:full]...)
+        Expect(crypt(block.NewCBCCTSEncrypter(b, iv, block.CS1), plaintext)).To(Equal(expected))
+      }
+    })
+
+    It("should round-trip input of any length", func() {
+      for _, variant := range variants {
+        for n := aes.BlockSize; n <= 5*aes.BlockSize; n++ {
+          src := randomBytes(n)
+          buf := append([]byte(nil), src...)
+
+          block.NewCBCCTSEncrypter(newAES(), aesIV, variant).CryptBlocks(buf, buf)
+          Expect(buf).NotTo(Equal(src))
+          block.NewCBCCTSDecrypter(newAES(), aesIV, variant).CryptBlocks(buf, buf)
+          Expect(buf).To(Equal(src), "plaintext of %d bytes (CS%d) should be equal", n, variant)
+        }
+      }
+    })
+  })
+
+  Context("ECB", func() {
+    It("should be the same as ECB for aligned input", func() {
+      src := randomBytes(64)
+      expected := make([]byte, len(src))
+      block.NewECBEncrypter(newAES()).CryptBlocks(expected, src)
+
+      for _, variant := range []block.CTSVariant{block.CS1, block.CS2} {
+        dst := make([]byte, len(src))
+        block.NewECBCTSEncrypter(newAES(), variant).CryptBlocks(dst, src)
+        Expect(dst).To(Equal(expected))
+      }
+    })
+
+    It("should steal the tail of the previous ciphertext block", func() {
+      src := randomBytes(40)
+      dst := make([]byte, len(src))
+      block.NewECBCTSEncrypter(newAES(), block.CS1).CryptBlocks(dst, src)
+
+      // first block is not affected
+      expected := make([]byte, 16)
+      newAES().Encrypt(expected, src[:16])
+      Expect(dst[:16]).To(Equal(expected))
+
+      // last block is the encryption of the last plaintext bytes padded with the tail of the previous ciphertext block
+      newAES().Encrypt(expected, src[16:32])
+      last := append(append([]byte(nil), src[32:]...), expected[8:]...)
+      newAES().Encrypt(last, last)
+      Expect(dst[16:24]).To(Equal(expected[:8]))
+      Expect(dst[24:]).To(Equal(last))
+    })
+
+    It("should round-trip input of any length", func() {
+      for _, variant := range variants {
+        for n := aes.BlockSize; n <= 5*aes.BlockSize; n++ {
+          src := randomBytes(n)
+          buf := append([]byte(nil), src...)
+
+          block.NewECBCTSEncrypter(newAES(), variant).CryptBlocks(buf, buf)
+          Expect(buf).NotTo(Equal(src))
+          block.NewECBCTSDecrypter(newAES(), variant).CryptBlocks(buf, buf)
+          Expect(buf).To(Equal(src), "plaintext of %d bytes (CS%d) should be equal", n, variant)
+        }
+      }
+    })
+  })
+})
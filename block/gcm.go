@@ -0,0 +1,177 @@
+package block
+
+import (
+  "crypto/cipher"
+  "crypto/subtle"
+  "encoding/binary"
+  "errors"
+  "fmt"
+)
+
+const (
+  gcmBlockSize         = 16
+  gcmTagSize           = 16
+  gcmStandardNonceSize = 12
+)
+
+var (
+  // ErrAuthenticationFailed is returned by the Open method of GCM if the authentication tag doesn't match the
+  // ciphertext and additional data, i.e. the ciphertext, additional data, nonce or tag has been tampered with (or the
+  // wrong key was used).
+  ErrAuthenticationFailed = errors.New("grypto/gcm: message authentication failed")
+  // ErrCiphertextTooShort is returned by the Open method of GCM if the ciphertext is too short to contain a tag.
+  ErrCiphertextTooShort = errors.New("grypto/gcm: ciphertext too short to contain authentication tag")
+)
+
+type gcm struct {
+  block     cipher.Block
+  nonceSize int
+  // h is the hash subkey, the encryption of the zero block
+  h fieldElement
+}
+
+// NewGCM returns a new cipher.AEAD which uses the given Block cipher in Galois/Counter mode (GCM) with the standard
+// nonce length of 12 bytes. GCM is an authenticated encryption mode, that not only protects the confidentiality but
+// also the integrity and authenticity of the data. In contrast to ECB or CBC, an attacker can't modify the
+// ciphertext without being detected.
+// The plaintext is encrypted in counter mode and an authentication tag is calculated over the ciphertext and
+// additional data (which is authenticated, but not encrypted) using GHASH, a polynomial hash over the finite field
+// GF(2¹²⁸). The tag is encrypted with the first counter block and appended to the ciphertext.
+// GCM only works with block ciphers with a block size of 16 bytes. A nonce must never be reused with the same key.
+// See https://en.wikipedia.org/wiki/Galois/Counter_Mode and NIST SP 800-38D.
+func NewGCM(block cipher.Block) (cipher.AEAD, error) {
+  return NewGCMWithNonceSize(block, gcmStandardNonceSize)
+}
+
+// NewGCMWithNonceSize returns a new cipher.AEAD like NewGCM, which accepts nonces of the given length.
+// Only use this for compatibility with existing cryptosystems, that use non-standard nonce lengths.
+func NewGCMWithNonceSize(block cipher.Block, size int) (cipher.AEAD, error) {
+  if block.BlockSize() != gcmBlockSize {
+    return nil, fmt.Errorf("grypto/gcm: block size must be %d, got %d", gcmBlockSize, block.BlockSize())
+  }
+  if size <= 0 {
+    return nil, errors.New("grypto/gcm: nonce size must be greater than 0")
+  }
+
+  var h [gcmBlockSize]byte
+  block.Encrypt(h[:], h[:])
+
+  return &gcm{
+    block:     block,
+    nonceSize: size,
+    h:         newFieldElement(h[:]),
+  }, nil
+}
+
+func (g *gcm) NonceSize() int {
+  return g.nonceSize
+}
+
+func (g *gcm) Overhead() int {
+  return gcmTagSize
+}
+
+func (g *gcm) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+  if len(nonce) != g.nonceSize {
+    panic("grypto/gcm: incorrect nonce length given to GCM")
+  }
+
+  ret, out := sliceForAppend(dst, len(plaintext)+gcmTagSize)
+
+  var counter, tagMask [gcmBlockSize]byte
+  g.initCounter(&counter, nonce)
+  g.block.Encrypt(tagMask[:], counter[:])
+
+  // encrypt plaintext in counter mode starting with the second counter block
+  incrementCounter(&counter)
+  g.counterMode(out[:len(plaintext)], plaintext, &counter)
+
+  g.tag(out[len(plaintext):], out[:len(plaintext)], additionalData, &tagMask)
+  return ret
+}
+
+func (g *gcm) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+  if len(nonce) != g.nonceSize {
+    panic("grypto/gcm: incorrect nonce length given to GCM")
+  }
+  if len(ciphertext) < gcmTagSize {
+    return nil, ErrCiphertextTooShort
+  }
+
+  tag := ciphertext[len(ciphertext)-gcmTagSize:]
+  ciphertext = ciphertext[:len(ciphertext)-gcmTagSize]
+
+  var counter, tagMask, expectedTag [gcmBlockSize]byte
+  g.initCounter(&counter, nonce)
+  g.block.Encrypt(tagMask[:], counter[:])
+
+  // verify the tag before decrypting anything, never return unauthenticated plaintext
+  g.tag(expectedTag[:], ciphertext, additionalData, &tagMask)
+  if subtle.ConstantTimeCompare(expectedTag[:], tag) != 1 {
+    return nil, ErrAuthenticationFailed
+  }
+
+  ret, out := sliceForAppend(dst, len(ciphertext))
+  incrementCounter(&counter)
+  g.counterMode(out, ciphertext, &counter)
+
+  return ret, nil
+}
+
+// initCounter calculates the first counter block J₀ from the given nonce.
+func (g *gcm) initCounter(counter *[gcmBlockSize]byte, nonce []byte) {
+  if len(nonce) == gcmStandardNonceSize {
+    // J₀ = nonce || 0³¹ || 1
+    copy(counter[:], nonce)
+    counter[gcmBlockSize-1] = 1
+    return
+  }
+
+  // J₀ = GHASH(nonce || 0-padding || 0⁶⁴ || bit length of nonce)
+  h := &ghash{h: g.h}
+  h.update(nonce)
+  h.sum(counter[:], 0, len(nonce))
+}
+
+// counterMode XORs src with the key stream generated by encrypting consecutive counter blocks.
+// In contrast to NewCTR, only the last 32 bits of the counter block are incremented.
+func (g *gcm) counterMode(dst, src []byte, counter *[gcmBlockSize]byte) {
+  var keyStream [gcmBlockSize]byte
+
+  for len(src) > 0 {
+    g.block.Encrypt(keyStream[:], counter[:])
+    incrementCounter(counter)
+
+    n := xorBytes(dst, src, keyStream[:])
+    src = src[n:]
+    dst = dst[n:]
+  }
+}
+
+// tag calculates the authentication tag over ciphertext and additional data and writes it to dst.
+func (g *gcm) tag(dst, ciphertext, additionalData []byte, tagMask *[gcmBlockSize]byte) {
+  h := &ghash{h: g.h}
+  h.update(additionalData)
+  h.update(ciphertext)
+  h.sum(dst, len(additionalData), len(ciphertext))
+
+  xorBytes(dst, dst, tagMask[:])
+}
+
+// incrementCounter increments the last 32 bits of the counter block (wrapping around on overflow).
+func incrementCounter(counter *[gcmBlockSize]byte) {
+  c := counter[gcmBlockSize-4:]
+  binary.BigEndian.PutUint32(c, binary.BigEndian.Uint32(c)+1)
+}
+
+// sliceForAppend extends in by n bytes. It returns the extended slice (head) and the n new bytes (tail).
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+  if total := len(in) + n; cap(in) >= total {
+    head = in[:total]
+  } else {
+    head = make([]byte, total)
+    copy(head, in)
+  }
+  tail = head[len(in):]
+  return
+}
@@ -0,0 +1,138 @@
+package block_test
+
+import (
+  "crypto/aes"
+  "crypto/cipher"
+  "encoding/hex"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/block"
+  "github.com/timebertt/grypto/caesar"
+)
+
+var _ = Describe("GCM", func() {
+  newGCM := func(key []byte, nonceSize int) (actual, expected cipher.AEAD) {
+    b, err := aes.NewCipher(key)
+    ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+    actual, err = block.NewGCMWithNonceSize(b, nonceSize)
+    ExpectWithOffset(1, err).NotTo(HaveOccurred())
+    expected, err = cipher.NewGCMWithNonceSize(b, nonceSize)
+    ExpectWithOffset(1, err).NotTo(HaveOccurred())
+    return
+  }
+
+  It("should reject block ciphers with a block size other than 16", func() {
+    _, err := block.NewGCM(caesar.NewCipher(3))
+    Expect(err).To(HaveOccurred())
+  })
+
+  It("should panic on incorrect nonce length", func() {
+    g, _ := newGCM(aesKey, 12)
+    Expect(func() {
+      g.Seal(nil, make([]byte, 8), nil, nil)
+    }).To(Panic())
+    Expect(func() {
+      _, _ = g.Open(nil, make([]byte, 8), make([]byte, 16), nil)
+    }).To(Panic())
+  })
+
+  It("should match the test vector from the GCM specification", func() {
+    // test case 2 from "The Galois/Counter Mode of Operation (GCM)", McGrew and Viega
+    b, err := aes.NewCipher(make([]byte, 16))
+    Expect(err).NotTo(HaveOccurred())
+    g, err := block.NewGCM(b)
+    Expect(err).NotTo(HaveOccurred())
+
+    out := g.Seal(nil, make([]byte, 12), make([]byte, 16), nil)
+    Expect(hex.EncodeToString(out)).To(Equal("0388dace60b6a392f328c2b971b2fe78" + "ab6e47d42cec13bdf53a67b21257bddf"))
+  })
+
+  It("should produce the same output as crypto/cipher", func() {
+    for _, keySize := range []int{16, 24, 32} {
+      for _, nonceSize := range []int{12, 8, 16, 60} {
+        actual, expected := newGCM(randomBytes(keySize), nonceSize)
+        nonce := randomBytes(nonceSize)
+
+        for _, n := range []int{0, 1, 15, 16, 17, 100, 1000} {
+          for _, additionalData := range [][]byte{nil, randomBytes(5), randomBytes(40)} {
+            plaintext := randomBytes(n)
+
+            ciphertext := actual.Seal(nil, nonce, plaintext, additionalData)
+            Expect(ciphertext).To(Equal(expected.Seal(nil, nonce, plaintext, additionalData)),
+              "ciphertext should be equal (key size %d, nonce size %d, plaintext size %d)", keySize, nonceSize, n)
+
+            out, err := actual.Open(nil, nonce, ciphertext, additionalData)
+            Expect(err).NotTo(HaveOccurred())
+            Expect(out).To(Equal(plaintext))
+          }
+        }
+      }
+    }
+  })
+
+  It("should seal and open in place and append to dst", func() {
+    g, _ := newGCM(aesKey, 12)
+    nonce := randomBytes(12)
+    plaintext := randomBytes(100)
+
+    buf := append([]byte(nil), plaintext...)
+    ciphertext := g.Seal(buf[:0], nonce, buf, nil)
+    Expect(ciphertext).To(HaveLen(len(plaintext) + g.Overhead()))
+
+    out, err := g.Open(ciphertext[:0], nonce, ciphertext, nil)
+    Expect(err).NotTo(HaveOccurred())
+    Expect(out).To(Equal(plaintext))
+
+    prefix := []byte("prefix")
+    out = g.Seal(prefix, nonce, plaintext, nil)
+    Expect(out[:len(prefix)]).To(Equal(prefix))
+  })
+
+  Context("tampering", func() {
+    var (
+      g                                 cipher.AEAD
+      nonce, additionalData, ciphertext []byte
+    )
+
+    BeforeEach(func() {
+      g, _ = newGCM(aesKey, 12)
+      nonce = randomBytes(12)
+      additionalData = []byte("header")
+      ciphertext = g.Seal(nil, nonce, randomBytes(50), additionalData)
+    })
+
+    It("should reject modified ciphertext", func() {
+      ciphertext[10] ^= 1
+      _, err := g.Open(nil, nonce, ciphertext, additionalData)
+      Expect(err).To(MatchError(block.ErrAuthenticationFailed))
+    })
+
+    It("should reject modified tag", func() {
+      ciphertext[len(ciphertext)-1] ^= 1
+      _, err := g.Open(nil, nonce, ciphertext, additionalData)
+      Expect(err).To(MatchError(block.ErrAuthenticationFailed))
+    })
+
+    It("should reject modified additional data", func() {
+      _, err := g.Open(nil, nonce, ciphertext, []byte("Header"))
+      Expect(err).To(MatchError(block.ErrAuthenticationFailed))
+    })
+
+    It("should reject wrong nonce", func() {
+      nonce[0] ^= 1
+      _, err := g.Open(nil, nonce, ciphertext, additionalData)
+      Expect(err).To(MatchError(block.ErrAuthenticationFailed))
+    })
+
+    It("should reject truncated ciphertext", func() {
+      _, err := g.Open(nil, nonce, ciphertext[:len(ciphertext)-1], additionalData)
+      Expect(err).To(MatchError(block.ErrAuthenticationFailed))
+
+      _, err = g.Open(nil, nonce, ciphertext[:15], additionalData)
+      Expect(err).To(MatchError(block.ErrCiphertextTooShort))
+    })
+  })
+})
@@ -0,0 +1,94 @@
+package block
+
+import "encoding/binary"
+
+// fieldElement is an element of the finite field GF(2¹²⁸) as used by GHASH. Each bit represents a coefficient of a
+// polynomial of degree < 128 in "reflected" order: the most significant bit of hi is the coefficient of x⁰ and the
+// least significant bit of lo is the coefficient of x¹²⁷.
+type fieldElement struct {
+  hi, lo uint64
+}
+
+// ghashReductionPoly is the GCM reduction polynomial x¹²⁸ + x⁷ + x² + x + 1 in bit-reflected order without the x¹²⁸
+// term: the coefficients of x⁰, x¹, x² and x⁷ are the most significant bits 11100001 = 0xe1 of hi.
+const ghashReductionPoly = 0xe1 << 56
+
+func newFieldElement(b []byte) fieldElement {
+  return fieldElement{
+    hi: binary.BigEndian.Uint64(b[:8]),
+    lo: binary.BigEndian.Uint64(b[8:16]),
+  }
+}
+
+func (x fieldElement) bytes(b []byte) {
+  binary.BigEndian.PutUint64(b[:8], x.hi)
+  binary.BigEndian.PutUint64(b[8:16], x.lo)
+}
+
+// add returns x + y in GF(2¹²⁸), which is a simple XOR of both polynomials' coefficients.
+func (x fieldElement) add(y fieldElement) fieldElement {
+  return fieldElement{hi: x.hi ^ y.hi, lo: x.lo ^ y.lo}
+}
+
+// mul returns x * y in GF(2¹²⁸) using the simple shift-and-add method (see algorithm 1 in NIST SP 800-38D).
+// For every coefficient of x, y is multiplied by x (shifted by one bit) and reduced modulo the reduction polynomial.
+// If the coefficient is set, the current multiple of y is added to the result.
+func (x fieldElement) mul(y fieldElement) fieldElement {
+  var z fieldElement
+  v := y
+
+  for i := 0; i < 128; i++ {
+    // coefficient of xⁱ
+    var bit uint64
+    if i < 64 {
+      bit = x.hi >> (63 - i) & 1
+    } else {
+      bit = x.lo >> (127 - i) & 1
+    }
+    if bit == 1 {
+      z = z.add(v)
+    }
+
+    // v = v * x mod (x¹²⁸ + x⁷ + x² + x + 1)
+    overflow := v.lo & 1
+    v.lo = v.lo>>1 | v.hi<<63
+    v.hi >>= 1
+    if overflow == 1 {
+      v.hi ^= ghashReductionPoly
+    }
+  }
+
+  return z
+}
+
+// ghash is the universal hash function used by GCM for authentication. It is keyed by the hash subkey h and
+// calculates a polynomial in h with the given input blocks as coefficients.
+type ghash struct {
+  h fieldElement
+  y fieldElement
+}
+
+// update processes the given input, the last incomplete block is padded with zeros.
+func (g *ghash) update(p []byte) {
+  var block [16]byte
+  for len(p) > 0 {
+    n := copy(block[:], p)
+    for i := n; i < len(block); i++ {
+      block[i] = 0
+    }
+
+    // y = (y + block) * h
+    g.y = g.y.add(newFieldElement(block[:])).mul(g.h)
+    p = p[n:]
+  }
+}
+
+// sum processes the length block (bit lengths of additional data and ciphertext) and writes the result to b.
+func (g *ghash) sum(b []byte, additionalDataLen, ciphertextLen int) {
+  var lengths [16]byte
+  binary.BigEndian.PutUint64(lengths[:8], uint64(additionalDataLen)*8)
+  binary.BigEndian.PutUint64(lengths[8:], uint64(ciphertextLen)*8)
+  g.update(lengths[:])
+
+  g.y.bytes(b)
+}
@@ -3,62 +3,425 @@ package block
 import (
   "crypto/cipher"
   "errors"
+  "fmt"
   "io"
 )
 
+// readerBufferSize is the size of the buffer used by the block mode reader's WriteTo.
+const readerBufferSize = 32 * 1024
+
 type reader struct {
   blockSize int
   blockMode cipher.BlockMode
   in        io.Reader
+
+  // padding is applied to the last block before crypting it (or removed after crypting it if unpad is set)
+  padding Padding
+  unpad   bool
+  // minRead is the minimum number of bytes to read from in for making progress
+  minRead int
+
+  // pending holds the input read from in, that was not crypted yet: an incomplete block and the last full block when
+  // removing padding (the last block has to be detected before returning it)
+  pending []byte
+  // out holds crypted output, that didn't fit into the buffer given to Read
+  out []byte
+  // scratch is used for crypting, if the buffer given to Read is too small
+  scratch []byte
+
+  eof bool
+  err error
 }
 
-// NewBlockModeReader returns a io.Reader that crypts each block read from in with the given cipher.BlockMode.
-func NewBlockModeReader(blockMode cipher.BlockMode, in io.Reader) io.Reader {
+func newReader(blockMode cipher.BlockMode, padding Padding, unpad bool, in io.Reader) *reader {
+  blockSize := blockMode.BlockSize()
+
+  minRead := blockSize
+  if unpad {
+    // hold back the last full block, until we know if it's the last one
+    minRead = 2 * blockSize
+  }
+
   return &reader{
-    blockSize: blockMode.BlockSize(),
+    blockSize: blockSize,
     blockMode: blockMode,
     in:        in,
+    padding:   padding,
+    unpad:     unpad,
+    minRead:   minRead,
+    pending:   make([]byte, 0, minRead),
   }
 }
 
+// NewBlockModeReader returns a io.Reader that crypts each block read from in with the given cipher.BlockMode.
+// Each call to Read crypts as many full blocks as fit into the given buffer, incomplete blocks are buffered until
+// they are filled by subsequent reads from in.
+func NewBlockModeReader(blockMode cipher.BlockMode, in io.Reader) io.Reader {
+  return newReader(blockMode, nil, false, in)
+}
+
+// NewPaddingBlockModeReader returns a io.Reader that crypts each block read from in with the given cipher.BlockMode.
+// The last block read from in is padded with the given Padding before crypting it, so the input doesn't need to be a
+// multiple of the block size. Hence, it should be used with an encrypting cipher.BlockMode.
+func NewPaddingBlockModeReader(blockMode cipher.BlockMode, padding Padding, in io.Reader) io.Reader {
+  return newReader(blockMode, padding, false, in)
+}
+
+// NewUnpaddingBlockModeReader returns a io.Reader that crypts each block read from in with the given
+// cipher.BlockMode. The given Padding is removed from the last block after crypting it and an error is returned if
+// it is not correctly padded. Hence, it should be used with a decrypting cipher.BlockMode.
+func NewUnpaddingBlockModeReader(blockMode cipher.BlockMode, padding Padding, in io.Reader) io.Reader {
+  return newReader(blockMode, padding, true, in)
+}
+
 // Read implements io.Reader.
 func (b *reader) Read(p []byte) (int, error) {
-  // we must be able to read at least a full block
-  if len(p) < b.blockSize {
-    return 0, io.ErrShortBuffer
+  // return output left over from previous reads first
+  if len(b.out) > 0 {
+    n := copy(p, b.out)
+    b.out = b.out[n:]
+    return n, nil
+  }
+  if b.err != nil {
+    return 0, b.err
+  }
+  if len(p) == 0 {
+    return 0, nil
+  }
+
+  if len(p) < b.minRead {
+    // p is too small for making progress, crypt into scratch buffer and return the output piecewise
+    if b.scratch == nil {
+      b.scratch = make([]byte, b.minRead)
+    }
+    b.out = b.scratch[:b.fill(b.scratch)]
+    return b.Read(p)
+  }
+
+  n := b.fill(p)
+  if n == 0 {
+    return 0, b.err
+  }
+  return n, nil
+}
+
+// WriteTo implements io.WriterTo. It is used by io.Copy and avoids allocating a buffer per copy.
+func (b *reader) WriteTo(w io.Writer) (int64, error) {
+  var written int64
+
+  // write output left over from previous reads first
+  if len(b.out) > 0 {
+    n, err := w.Write(b.out)
+    written += int64(n)
+    b.out = b.out[n:]
+    if err != nil {
+      return written, err
+    }
+  }
+
+  bufferSize := readerBufferSize / b.blockSize * b.blockSize
+  if bufferSize < b.minRead {
+    bufferSize = b.minRead
+  }
+  buf := make([]byte, bufferSize)
+
+  for b.err == nil {
+    n := b.fill(buf)
+    if n == 0 {
+      continue
+    }
+
+    m, err := w.Write(buf[:n])
+    written += int64(m)
+    if err != nil {
+      return written, err
+    }
+  }
+
+  if b.err == io.EOF {
+    return written, nil
+  }
+  return written, b.err
+}
+
+// fill reads as many full blocks from in as fit into p and crypts them. It returns the number of crypted bytes
+// in p. If no bytes are returned, b.err is set. p must be able to hold at least b.minRead bytes.
+func (b *reader) fill(p []byte) int {
+  max := len(p) / b.blockSize * b.blockSize
+
+  // start with input left over from previous reads
+  n := copy(p, b.pending)
+  b.pending = b.pending[:0]
+
+  // read at least minRead bytes, but don't block for filling up p entirely
+  for n < max && !b.eof {
+    m, err := b.in.Read(p[n:max])
+    n += m
+    if err == io.EOF {
+      b.eof = true
+    } else if err != nil {
+      b.err = err
+      return 0
+    }
+
+    if n >= b.minRead {
+      break
+    }
+  }
+
+  if b.eof {
+    return b.fillLast(p, n)
   }
 
-  // read one block from in
-  n, err := b.in.Read(p[:b.blockSize])
-  if err != nil {
-    if err != io.EOF {
-      return 0, err
+  whole := n / b.blockSize * b.blockSize
+  if b.unpad && whole == n {
+    // this might be the last block, hold it back
+    whole -= b.blockSize
+  }
+  b.pending = append(b.pending, p[whole:n]...)
+
+  return b.crypt(p[:whole])
+}
+
+// fillLast crypts the last n bytes read from in (already contained in p) and handles padding.
+func (b *reader) fillLast(p []byte, n int) int {
+  whole := n / b.blockSize * b.blockSize
+
+  switch {
+  case b.padding == nil:
+    b.err = io.EOF
+    if whole < n {
+      b.err = io.ErrUnexpectedEOF
+    }
+    return b.crypt(p[:whole])
+
+  case b.unpad:
+    if whole < n {
+      b.err = io.ErrUnexpectedEOF
+      return 0
     }
-    // only return EOF, if we didn't read anything
     if n == 0 {
-      return 0, err
+      // empty input, check if that is valid for the padding scheme
+      if _, b.err = b.padding.Unpad(nil, b.blockSize); b.err == nil {
+        b.err = io.EOF
+      }
+      return 0
+    }
+
+    if b.crypt(p[:n]) == 0 {
+      return 0
+    }
+    unpadded, err := b.padding.Unpad(p[:n], b.blockSize)
+    if err != nil {
+      b.err = err
+      return 0
     }
+    b.err = io.EOF
+    return len(unpadded)
+
+  default:
+    padded := b.padding.Pad(p[whole:n], b.blockSize)
+    if whole+len(padded) > len(p) {
+      // padding doesn't fit into p anymore, crypt full blocks and pad the rest on the next read
+      b.pending = append(b.pending, p[whole:n]...)
+      return b.crypt(p[:whole])
+    }
+
+    n = whole + copy(p[whole:], padded)
+    b.err = io.EOF
+    return b.crypt(p[:n])
   }
-  if n < b.blockSize {
-    return 0, io.ErrUnexpectedEOF
+}
+
+// crypt crypts p in place and returns len(p). If crypting fails, b.err is set and 0 is returned.
+func (b *reader) crypt(p []byte) int {
+  if err := cryptBlocks(b.blockMode, p, p); err != nil {
+    b.err = err
+    return 0
   }
+  return len(p)
+}
 
-  func() {
-    // cipher.BlockMode doesn't allow to return an error, so implementations tend to panic on error,
-    // catch those errors here.
-    defer func() {
-      if p := recover(); p != nil {
-        if e, ok := p.(error); ok {
-          err = e
-        } else if s, ok := p.(string); ok {
-          err = errors.New(s)
-        }
+// cryptBlocks crypts src into dst using the given cipher.BlockMode.
+func cryptBlocks(blockMode cipher.BlockMode, dst, src []byte) (err error) {
+  // cipher.BlockMode doesn't allow to return an error, so implementations tend to panic on error,
+  // catch those errors here.
+  defer func() {
+    if p := recover(); p != nil {
+      if e, ok := p.(error); ok {
+        err = e
+      } else if s, ok := p.(string); ok {
+        err = errors.New(s)
+      } else {
+        err = fmt.Errorf("%v", p)
       }
-    }()
-    b.blockMode.CryptBlocks(p[:b.blockSize], p[:b.blockSize])
+    }
   }()
-  if err != nil {
-    return 0, err
+
+  blockMode.CryptBlocks(dst, src)
+  return nil
+}
+
+// writerBufferSize is the default size of the buffer used by the block mode writer.
+const writerBufferSize = 4096
+
+// errWriterClosed is returned when writing to an already closed block mode writer.
+var errWriterClosed = errors.New("grypto/block: write to closed writer")
+
+type writer struct {
+  blockSize int
+  blockMode cipher.BlockMode
+  out       io.Writer
+
+  // padding is applied to the last block before crypting it (or removed after crypting it if unpad is set)
+  padding Padding
+  unpad   bool
+
+  // buf holds the input that was not crypted and written yet: an incomplete block or the last full block when
+  // removing padding (the last block has to be detected before writing it)
+  buf    []byte
+  err    error
+  closed bool
+}
+
+func newWriter(blockMode cipher.BlockMode, padding Padding, unpad bool, out io.Writer) *writer {
+  blockSize := blockMode.BlockSize()
+
+  // buffer full blocks only and at least two blocks, so that we can always hold back the last block
+  bufferSize := writerBufferSize / blockSize * blockSize
+  if bufferSize < 2*blockSize {
+    bufferSize = 2 * blockSize
+  }
+
+  return &writer{
+    blockSize: blockSize,
+    blockMode: blockMode,
+    out:       out,
+    padding:   padding,
+    unpad:     unpad,
+    buf:       make([]byte, 0, bufferSize),
+  }
+}
+
+// NewBlockModeWriter returns a io.WriteCloser that crypts each block written to it with the given cipher.BlockMode
+// and writes the result to out. Incomplete blocks are buffered until they are filled by subsequent writes.
+// Close must be called after writing the last block and returns an error if the input was not a multiple of the
+// block size. It doesn't close out.
+func NewBlockModeWriter(blockMode cipher.BlockMode, out io.Writer) io.WriteCloser {
+  return newWriter(blockMode, nil, false, out)
+}
+
+// NewPaddingBlockModeWriter returns a io.WriteCloser that crypts each block written to it with the given
+// cipher.BlockMode and writes the result to out. On Close, the last block is padded with the given Padding, crypted
+// and written to out, so the input doesn't need to be a multiple of the block size. Hence, it should be used with
+// an encrypting cipher.BlockMode. Close doesn't close out.
+func NewPaddingBlockModeWriter(blockMode cipher.BlockMode, padding Padding, out io.Writer) io.WriteCloser {
+  return newWriter(blockMode, padding, false, out)
+}
+
+// NewUnpaddingBlockModeWriter returns a io.WriteCloser that crypts each block written to it with the given
+// cipher.BlockMode and writes the result to out. The last block is held back until Close is called, which removes
+// the given Padding from it and returns an error if it is not correctly padded. Hence, it should be used with a
+// decrypting cipher.BlockMode. Close doesn't close out.
+func NewUnpaddingBlockModeWriter(blockMode cipher.BlockMode, padding Padding, out io.Writer) io.WriteCloser {
+  return newWriter(blockMode, padding, true, out)
+}
+
+// Write implements io.Writer.
+func (b *writer) Write(p []byte) (int, error) {
+  if b.closed {
+    return 0, errWriterClosed
+  }
+  if b.err != nil {
+    return 0, b.err
+  }
+
+  written := 0
+  for len(p) > 0 {
+    // fill up the buffer
+    n := copy(b.buf[len(b.buf):cap(b.buf)], p)
+    b.buf = b.buf[:len(b.buf)+n]
+    p = p[n:]
+
+    if err := b.flush(); err != nil {
+      b.err = err
+      return written, err
+    }
+    written += n
+  }
+
+  return written, nil
+}
+
+// flush crypts and writes all full blocks in the buffer (except the last one, when removing padding).
+func (b *writer) flush() error {
+  n := len(b.buf) / b.blockSize * b.blockSize
+  if b.unpad && n == len(b.buf) {
+    // this might be the last block, hold it back
+    n -= b.blockSize
+  }
+  if n <= 0 {
+    return nil
+  }
+
+  if err := b.write(b.buf[:n]); err != nil {
+    return err
+  }
+
+  // keep the rest for the next write
+  b.buf = b.buf[:copy(b.buf, b.buf[n:])]
+  return nil
+}
+
+// write crypts p in place and writes it to out.
+func (b *writer) write(p []byte) error {
+  if err := cryptBlocks(b.blockMode, p, p); err != nil {
+    return err
+  }
+  _, err := b.out.Write(p)
+  return err
+}
+
+// Close crypts and writes the remaining buffered input to out.
+func (b *writer) Close() error {
+  if b.closed {
+    return nil
+  }
+  b.closed = true
+  if b.err != nil {
+    return b.err
+  }
+
+  switch {
+  case b.padding == nil:
+    if len(b.buf) > 0 {
+      return io.ErrUnexpectedEOF
+    }
+    return nil
+  case b.unpad:
+    if len(b.buf) == 0 {
+      // empty input, check if that is valid for the padding scheme
+      _, err := b.padding.Unpad(nil, b.blockSize)
+      return err
+    }
+    if len(b.buf) != b.blockSize {
+      return io.ErrUnexpectedEOF
+    }
+
+    if err := cryptBlocks(b.blockMode, b.buf, b.buf); err != nil {
+      return err
+    }
+    unpadded, err := b.padding.Unpad(b.buf, b.blockSize)
+    if err != nil {
+      return err
+    }
+    _, err = b.out.Write(unpadded)
+    return err
+  default:
+    padded := b.padding.Pad(b.buf, b.blockSize)
+    if len(padded) == 0 {
+      return nil
+    }
+    return b.write(padded)
   }
-  return b.blockSize, nil
 }
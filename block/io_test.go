@@ -0,0 +1,336 @@
+package block_test
+
+import (
+  "bytes"
+  "crypto/cipher"
+  "io"
+  "io/ioutil"
+  "testing"
+  "testing/iotest"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/block"
+  "github.com/timebertt/grypto/caesar"
+)
+
+var _ = Describe("BlockModeReader", func() {
+  It("should crypt full blocks", func() {
+    src := randomBytes(64)
+    expected := make([]byte, len(src))
+    cipher.NewCBCEncrypter(newAES(), aesIV).CryptBlocks(expected, src)
+
+    r := block.NewBlockModeReader(block.NewCBCEncrypter(newAES(), aesIV), bytes.NewReader(src))
+    out, err := ioutil.ReadAll(r)
+    Expect(err).NotTo(HaveOccurred())
+    Expect(out).To(Equal(expected))
+  })
+
+  It("should fail on input not full blocks", func() {
+    r := block.NewBlockModeReader(block.NewCBCEncrypter(newAES(), aesIV), bytes.NewReader(randomBytes(20)))
+    _, err := ioutil.ReadAll(r)
+    Expect(err).To(MatchError(io.ErrUnexpectedEOF))
+  })
+
+  It("should crypt as many blocks as fit into the buffer", func() {
+    src := randomBytes(100)
+    expected := make([]byte, 96)
+    cipher.NewCBCEncrypter(newAES(), aesIV).CryptBlocks(expected, src[:96])
+
+    r := block.NewBlockModeReader(block.NewCBCEncrypter(newAES(), aesIV), bytes.NewReader(src))
+    p := make([]byte, 50)
+    n, err := r.Read(p)
+    Expect(err).NotTo(HaveOccurred())
+    Expect(p[:n]).To(Equal(expected[:48]))
+
+    p = make([]byte, 100)
+    n, err = r.Read(p)
+    Expect(err).NotTo(HaveOccurred())
+    Expect(p[:n]).To(Equal(expected[48:]))
+
+    _, err = r.Read(p)
+    Expect(err).To(MatchError(io.ErrUnexpectedEOF))
+  })
+
+  It("should read into buffers smaller than a block", func() {
+    src := randomBytes(64)
+    expected := make([]byte, len(src))
+    cipher.NewCBCEncrypter(newAES(), aesIV).CryptBlocks(expected, src)
+
+    r := block.NewBlockModeReader(block.NewCBCEncrypter(newAES(), aesIV), bytes.NewReader(src))
+    out, err := ioutil.ReadAll(iotest.OneByteReader(r))
+    Expect(err).NotTo(HaveOccurred())
+    Expect(out).To(Equal(expected))
+  })
+
+  It("should implement io.WriterTo", func() {
+    src := randomBytes(100000)
+    expected := make([]byte, len(src))
+    cipher.NewCBCEncrypter(newAES(), aesIV).CryptBlocks(expected, src)
+
+    r := block.NewBlockModeReader(block.NewCBCEncrypter(newAES(), aesIV), iotest.HalfReader(bytes.NewReader(src)))
+    writerTo, ok := r.(io.WriterTo)
+    Expect(ok).To(BeTrue())
+
+    out := &bytes.Buffer{}
+    n, err := writerTo.WriteTo(out)
+    Expect(err).NotTo(HaveOccurred())
+    Expect(n).To(BeEquivalentTo(len(src)))
+    Expect(out.Bytes()).To(Equal(expected))
+  })
+
+  It("should work with the caesar cipher", func() {
+    r := block.NewBlockModeReader(block.NewECBEncrypter(caesar.NewCipher(3)), bytes.NewBufferString("Caesar"))
+    out, err := ioutil.ReadAll(r)
+    Expect(err).NotTo(HaveOccurred())
+    Expect(string(out)).To(Equal("Fdhvdu"))
+  })
+
+  Context("padding", func() {
+    encrypt := func(src []byte, padding block.Padding) []byte {
+      out, err := ioutil.ReadAll(block.NewPaddingBlockModeReader(block.NewCBCEncrypter(newAES(), aesIV), padding,
+        iotest.HalfReader(bytes.NewReader(src))))
+      ExpectWithOffset(1, err).NotTo(HaveOccurred())
+      return out
+    }
+
+    decrypt := func(src []byte, padding block.Padding) ([]byte, error) {
+      return ioutil.ReadAll(block.NewUnpaddingBlockModeReader(block.NewCBCDecrypter(newAES(), aesIV), padding,
+        iotest.HalfReader(bytes.NewReader(src))))
+    }
+
+    It("should pad the last block", func() {
+      for _, n := range []int{0, 1, 15, 16, 17, 100} {
+        src := randomBytes(n)
+        padded := block.PKCS7.Pad(src, 16)
+        expected := make([]byte, len(padded))
+        cipher.NewCBCEncrypter(newAES(), aesIV).CryptBlocks(expected, padded)
+
+        Expect(encrypt(src, block.PKCS7)).To(Equal(expected), "ciphertext of %d bytes should be equal", n)
+      }
+    })
+
+    It("should remove padding from the last block", func() {
+      for _, padding := range []block.Padding{block.PKCS7, block.ANSIX923, block.ISO7816, block.ISO10126} {
+        for _, n := range []int{0, 1, 15, 16, 17, 100} {
+          src := randomBytes(n)
+          out, err := decrypt(encrypt(src, padding), padding)
+          Expect(err).NotTo(HaveOccurred())
+          Expect(out).To(Equal(src), "plaintext of %d bytes should be equal", n)
+        }
+      }
+    })
+
+    It("should handle small buffers and io.WriterTo", func() {
+      for _, padding := range []block.Padding{block.PKCS7, block.ANSIX923, block.ISO7816, block.ISO10126} {
+        for _, n := range []int{0, 1, 15, 16, 17, 100, 100000} {
+          src := randomBytes(n)
+
+          r := block.NewPaddingBlockModeReader(block.NewCBCEncrypter(newAES(), aesIV), padding, bytes.NewReader(src))
+          ciphertext, err := ioutil.ReadAll(iotest.OneByteReader(r))
+          Expect(err).NotTo(HaveOccurred())
+
+          out := &bytes.Buffer{}
+          dec := block.NewCBCDecrypter(newAES(), aesIV)
+          _, err = io.Copy(out, block.NewUnpaddingBlockModeReader(dec, padding, bytes.NewReader(ciphertext)))
+          Expect(err).NotTo(HaveOccurred())
+          Expect(out.Bytes()).To(Equal(src), "plaintext of %d bytes should be equal", n)
+        }
+      }
+    })
+
+    It("should handle zero padding of empty and aligned input", func() {
+      for _, n := range []int{0, 16, 32} {
+        src := bytes.Repeat([]byte{'a'}, n)
+        ciphertext := encrypt(src, block.ZeroPadding)
+        Expect(ciphertext).To(HaveLen(n))
+
+        out, err := decrypt(ciphertext, block.ZeroPadding)
+        Expect(err).NotTo(HaveOccurred())
+        Expect(out).To(Equal(src))
+      }
+    })
+
+    It("should fail on invalid padding", func() {
+      ciphertext := encrypt(randomBytes(20), block.ISO7816)
+
+      _, err := decrypt(ciphertext, block.PKCS7)
+      Expect(err).To(MatchError(block.ErrInvalidPadding))
+    })
+
+    It("should fail on ciphertext not full blocks", func() {
+      _, err := decrypt(randomBytes(20), block.PKCS7)
+      Expect(err).To(MatchError(io.ErrUnexpectedEOF))
+
+      _, err = decrypt(nil, block.PKCS7)
+      Expect(err).To(MatchError(block.ErrNotFullBlocks))
+    })
+  })
+})
+
+// benchmarkReader reads 1 MiB through a block mode reader using the caesar cipher with buffers of the given size.
+func benchmarkReader(b *testing.B, bufferSize int) {
+  src := randomBytes(1 << 20)
+  p := make([]byte, bufferSize)
+  b.SetBytes(int64(len(src)))
+  b.ResetTimer()
+
+  for i := 0; i < b.N; i++ {
+    r := block.NewBlockModeReader(block.NewECBEncrypter(caesar.NewCipher(3)), bytes.NewReader(src))
+    for {
+      _, err := r.Read(p)
+      if err == io.EOF {
+        break
+      }
+      if err != nil {
+        b.Fatal(err)
+      }
+    }
+  }
+}
+
+// BenchmarkBlockModeReaderSingleBlock reads a single block per call, which was the behavior of the block mode reader
+// before batching multiple blocks.
+func BenchmarkBlockModeReaderSingleBlock(b *testing.B) {
+  benchmarkReader(b, caesar.BlockSize)
+}
+
+func BenchmarkBlockModeReaderBatch(b *testing.B) {
+  benchmarkReader(b, 32*1024)
+}
+
+func BenchmarkBlockModeReaderWriteTo(b *testing.B) {
+  src := randomBytes(1 << 20)
+  b.SetBytes(int64(len(src)))
+  b.ResetTimer()
+
+  for i := 0; i < b.N; i++ {
+    r := block.NewBlockModeReader(block.NewECBEncrypter(caesar.NewCipher(3)), bytes.NewReader(src))
+    if _, err := io.Copy(ioutil.Discard, r); err != nil {
+      b.Fatal(err)
+    }
+  }
+}
+
+var _ = Describe("BlockModeWriter", func() {
+  // writeChunks writes src to w in chunks of the given size and closes w
+  writeChunks := func(w io.WriteCloser, src []byte, chunkSize int) error {
+    for len(src) > 0 {
+      n := chunkSize
+      if n > len(src) {
+        n = len(src)
+      }
+      if _, err := w.Write(src[:n]); err != nil {
+        return err
+      }
+      src = src[n:]
+    }
+    return w.Close()
+  }
+
+  It("should crypt full blocks", func() {
+    src := randomBytes(10000)
+    expected := make([]byte, len(src))
+    cipher.NewCBCEncrypter(newAES(), aesIV).CryptBlocks(expected, src)
+
+    for _, chunkSize := range []int{1, 7, 16, 100, 5000} {
+      out := &bytes.Buffer{}
+      w := block.NewBlockModeWriter(block.NewCBCEncrypter(newAES(), aesIV), out)
+      Expect(writeChunks(w, src, chunkSize)).To(Succeed())
+      Expect(out.Bytes()).To(Equal(expected), "ciphertext for chunk size %d should be equal", chunkSize)
+    }
+  })
+
+  It("should fail on input not full blocks", func() {
+    w := block.NewBlockModeWriter(block.NewCBCEncrypter(newAES(), aesIV), ioutil.Discard)
+    Expect(writeChunks(w, randomBytes(20), 7)).To(MatchError(io.ErrUnexpectedEOF))
+  })
+
+  It("should fail on writing to a closed writer", func() {
+    w := block.NewBlockModeWriter(block.NewCBCEncrypter(newAES(), aesIV), ioutil.Discard)
+    Expect(w.Close()).To(Succeed())
+    _, err := w.Write(randomBytes(16))
+    Expect(err).To(HaveOccurred())
+  })
+
+  It("should return panics of the block mode as errors", func() {
+    w := block.NewBlockModeWriter(panickingBlockMode{}, ioutil.Discard)
+    _, err := w.Write(randomBytes(16))
+    Expect(err).To(MatchError("grypto/test: panic"))
+    Expect(w.Close()).To(MatchError("grypto/test: panic"))
+  })
+
+  It("should work with the caesar cipher", func() {
+    out := &bytes.Buffer{}
+    w := block.NewBlockModeWriter(block.NewECBEncrypter(caesar.NewCipher(3)), out)
+    Expect(writeChunks(w, []byte("Caesar"), 4)).To(Succeed())
+    Expect(out.String()).To(Equal("Fdhvdu"))
+  })
+
+  Context("padding", func() {
+    paddings := []block.Padding{block.PKCS7, block.ANSIX923, block.ISO7816, block.ISO10126}
+
+    It("should round-trip through writer and reader", func() {
+      for _, padding := range paddings {
+        for _, n := range []int{0, 1, 15, 16, 17, 100, 4096, 10000} {
+          src := randomBytes(n)
+
+          ciphertext := &bytes.Buffer{}
+          w := block.NewPaddingBlockModeWriter(block.NewCBCEncrypter(newAES(), aesIV), padding, ciphertext)
+          Expect(writeChunks(w, src, 33)).To(Succeed())
+
+          r := block.NewUnpaddingBlockModeReader(block.NewCBCDecrypter(newAES(), aesIV), padding, ciphertext)
+          out, err := ioutil.ReadAll(r)
+          Expect(err).NotTo(HaveOccurred())
+          Expect(out).To(Equal(src), "plaintext of %d bytes should be equal", n)
+        }
+      }
+    })
+
+    It("should round-trip through reader and writer", func() {
+      for _, padding := range paddings {
+        for _, n := range []int{0, 1, 15, 16, 17, 100, 4096, 10000} {
+          src := randomBytes(n)
+
+          r := block.NewPaddingBlockModeReader(block.NewCBCEncrypter(newAES(), aesIV), padding, bytes.NewReader(src))
+          ciphertext, err := ioutil.ReadAll(r)
+          Expect(err).NotTo(HaveOccurred())
+
+          out := &bytes.Buffer{}
+          w := block.NewUnpaddingBlockModeWriter(block.NewCBCDecrypter(newAES(), aesIV), padding, out)
+          Expect(writeChunks(w, ciphertext, 33)).To(Succeed())
+          Expect(out.Bytes()).To(Equal(src), "plaintext of %d bytes should be equal", n)
+        }
+      }
+    })
+
+    It("should fail on invalid padding", func() {
+      ciphertext := &bytes.Buffer{}
+      w := block.NewPaddingBlockModeWriter(block.NewCBCEncrypter(newAES(), aesIV), block.ISO7816, ciphertext)
+      Expect(writeChunks(w, randomBytes(20), 20)).To(Succeed())
+
+      w = block.NewUnpaddingBlockModeWriter(block.NewCBCDecrypter(newAES(), aesIV), block.PKCS7, ioutil.Discard)
+      Expect(writeChunks(w, ciphertext.Bytes(), 20)).To(MatchError(block.ErrInvalidPadding))
+    })
+
+    It("should fail on ciphertext not full blocks", func() {
+      w := block.NewUnpaddingBlockModeWriter(block.NewCBCDecrypter(newAES(), aesIV), block.PKCS7, ioutil.Discard)
+      Expect(writeChunks(w, randomBytes(20), 20)).To(MatchError(io.ErrUnexpectedEOF))
+
+      w = block.NewUnpaddingBlockModeWriter(block.NewCBCDecrypter(newAES(), aesIV), block.PKCS7, ioutil.Discard)
+      Expect(w.Close()).To(MatchError(block.ErrNotFullBlocks))
+    })
+  })
+})
+
+// panickingBlockMode is a cipher.BlockMode which always panics.
+type panickingBlockMode struct{}
+
+func (panickingBlockMode) BlockSize() int {
+  return 16
+}
+
+func (panickingBlockMode) CryptBlocks(_, _ []byte) {
+  panic("grypto/test: panic")
+}
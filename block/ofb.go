@@ -0,0 +1,52 @@
+package block
+
+import "crypto/cipher"
+
+type ofb struct {
+  block cipher.Block
+  // keyStream holds the current key stream block, which is encrypted again to generate the next one
+  keyStream []byte
+  // used is the number of bytes of keyStream that have already been used
+  used int
+}
+
+// NewOFB returns a new cipher.Stream which uses the given Block cipher to encrypt or decrypt in output feedback mode
+// (OFB). OFB turns a block cipher into a synchronous stream cipher: the initialization vector is repeatedly encrypted
+// to generate the key stream, which is then XORed with the input. The given IV must have the same length as the
+// Block's block size.
+// As the key stream doesn't depend on the plaintext or ciphertext, encryption and decryption are the exact same
+// operation and the key stream can be precomputed. However, reusing an IV with the same key reveals the XOR of both
+// plaintexts.
+// See https://en.wikipedia.org/wiki/Block_cipher_mode_of_operation#Output_feedback_(OFB)
+func NewOFB(block cipher.Block, iv []byte) cipher.Stream {
+  blockSize := block.BlockSize()
+  if len(iv) != blockSize {
+    panic("grypto/ofb: IV length must equal block size")
+  }
+
+  return &ofb{
+    block:     block,
+    keyStream: append([]byte(nil), iv...),
+    used:      blockSize,
+  }
+}
+
+func (o *ofb) XORKeyStream(dst, src []byte) {
+  if len(dst) < len(src) {
+    panic("grypto/ofb: output smaller than input")
+  }
+
+  for len(src) > 0 {
+    if o.used == len(o.keyStream) {
+      // generate next key stream block by encrypting the previous one
+      o.block.Encrypt(o.keyStream, o.keyStream)
+      o.used = 0
+    }
+
+    n := xorBytes(dst, src, o.keyStream[o.used:])
+
+    o.used += n
+    src = src[n:]
+    dst = dst[n:]
+  }
+}
@@ -0,0 +1,41 @@
+package block_test
+
+import (
+  "crypto/cipher"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/block"
+  "github.com/timebertt/grypto/caesar"
+)
+
+var _ = Describe("OFB", func() {
+  It("should panic on invalid IV length", func() {
+    Expect(func() {
+      block.NewOFB(newAES(), aesIV[:8])
+    }).To(Panic())
+  })
+
+  It("should produce the same output as crypto/cipher", func() {
+    test := func(n, chunkSize int) {
+      testStream(block.NewOFB(newAES(), aesIV), cipher.NewOFB(newAES(), aesIV), randomBytes(n), chunkSize)
+    }
+
+    test(1, 1)
+    test(15, 4)
+    test(16, 16)
+    test(100, 7)
+    test(4096, 1000)
+  })
+
+  It("should work with the caesar cipher", func() {
+    src := []byte("Caesar Cipher in OFB mode")
+    dst := make([]byte, len(src))
+
+    block.NewOFB(caesar.NewCipher(3), []byte{'x'}).XORKeyStream(dst, src)
+    Expect(dst).NotTo(Equal(src))
+    block.NewOFB(caesar.NewCipher(3), []byte{'x'}).XORKeyStream(dst, dst)
+    Expect(dst).To(Equal(src))
+  })
+})
@@ -0,0 +1,231 @@
+package block
+
+import (
+  "crypto/rand"
+  "errors"
+  "fmt"
+)
+
+var (
+  // ErrNotFullBlocks is returned by Padding.Unpad if the input is empty or its length is not a multiple of the block
+  // size.
+  ErrNotFullBlocks = errors.New("grypto/padding: input not full blocks")
+  // ErrInvalidPadding is returned by Padding.Unpad if the input is not correctly padded. Errors returned by Unpad
+  // wrap ErrInvalidPadding with a more detailed description, so they should be checked with errors.Is.
+  ErrInvalidPadding = errors.New("grypto/padding: invalid padding")
+)
+
+// Padding is a scheme for extending the input of block modes to a multiple of the block size.
+// Block modes like ECB and CBC can only operate on full blocks, so the last (incomplete) block of an arbitrary input
+// needs to be filled up with padding bytes before encryption. Most padding schemes add at least one byte (up to a
+// full block) of padding, so that the padding can be removed unambiguously after decryption.
+// See https://en.wikipedia.org/wiki/Padding_(cryptography)
+type Padding interface {
+  // Pad returns a copy of src extended by padding bytes, so that its length is a multiple of blockSize.
+  Pad(src []byte, blockSize int) []byte
+  // Unpad returns src without the padding bytes. It returns an error if src is not correctly padded.
+  Unpad(src []byte, blockSize int) ([]byte, error)
+}
+
+var (
+  // PKCS7 is the padding scheme defined in PKCS#7 (RFC 5652). It appends n bytes of value n.
+  // The block size must not be greater than 255.
+  PKCS7 Padding = pkcs7{}
+  // ANSIX923 is the padding scheme defined in ANSI X9.23. It appends n-1 zero bytes followed by a single byte of
+  // value n. The block size must not be greater than 255.
+  ANSIX923 Padding = ansiX923{}
+  // ISO7816 is the padding scheme defined in ISO/IEC 7816-4. It appends a single byte of value 0x80 followed by n-1
+  // zero bytes.
+  ISO7816 Padding = iso7816{}
+  // ISO10126 is the padding scheme defined in ISO 10126. It appends n-1 random bytes followed by a single byte of
+  // value n. The block size must not be greater than 255.
+  ISO10126 Padding = iso10126{}
+  // ZeroPadding fills up the last block with zero bytes. In contrast to the other schemes, it doesn't add any
+  // padding if the input is already a multiple of the block size. This means, that trailing zero bytes of the
+  // plaintext can't be distinguished from padding and are removed as well by Unpad. Hence, it should only be used
+  // for input that doesn't end with zero bytes (e.g. text).
+  ZeroPadding Padding = zeroPadding{}
+)
+
+// paddingLength returns the number of padding bytes needed for filling up the last block of an input of length n.
+// The result is always in range [1,blockSize].
+func paddingLength(n, blockSize int) int {
+  if blockSize <= 0 {
+    panic("grypto/padding: block size must be greater than 0")
+  }
+  return blockSize - n%blockSize
+}
+
+// lengthByteBlockSize panics, if the padding length can't be encoded in a single byte for the given block size.
+func lengthByteBlockSize(blockSize int) {
+  if blockSize > 255 {
+    panic("grypto/padding: block size must not be greater than 255")
+  }
+}
+
+// pad returns a copy of src with n additional bytes.
+func pad(src []byte, n int) []byte {
+  dst := make([]byte, len(src)+n)
+  copy(dst, src)
+  return dst
+}
+
+// checkFullBlocks returns ErrNotFullBlocks if src is empty or not a multiple of blockSize.
+func checkFullBlocks(src []byte, blockSize int) error {
+  if blockSize <= 0 {
+    panic("grypto/padding: block size must be greater than 0")
+  }
+  if len(src) == 0 || len(src)%blockSize != 0 {
+    return ErrNotFullBlocks
+  }
+  return nil
+}
+
+// lengthByte reads and validates the padding length encoded in the last byte of src.
+func lengthByte(src []byte, blockSize int) (int, error) {
+  lengthByteBlockSize(blockSize)
+  if err := checkFullBlocks(src, blockSize); err != nil {
+    return 0, err
+  }
+
+  n := int(src[len(src)-1])
+  if n == 0 || n > blockSize {
+    return 0, fmt.Errorf("%w: padding length %d out of range [1,%d]", ErrInvalidPadding, n, blockSize)
+  }
+  return n, nil
+}
+
+type pkcs7 struct{}
+
+func (pkcs7) Pad(src []byte, blockSize int) []byte {
+  lengthByteBlockSize(blockSize)
+  n := paddingLength(len(src), blockSize)
+
+  dst := pad(src, n)
+  for i := len(src); i < len(dst); i++ {
+    dst[i] = byte(n)
+  }
+  return dst
+}
+
+func (pkcs7) Unpad(src []byte, blockSize int) ([]byte, error) {
+  n, err := lengthByte(src, blockSize)
+  if err != nil {
+    return nil, err
+  }
+
+  for _, b := range src[len(src)-n:] {
+    if int(b) != n {
+      return nil, fmt.Errorf("%w: padding byte %#x should be %#x", ErrInvalidPadding, b, n)
+    }
+  }
+  return src[:len(src)-n], nil
+}
+
+type ansiX923 struct{}
+
+func (ansiX923) Pad(src []byte, blockSize int) []byte {
+  lengthByteBlockSize(blockSize)
+  n := paddingLength(len(src), blockSize)
+
+  // new bytes are already zero
+  dst := pad(src, n)
+  dst[len(dst)-1] = byte(n)
+  return dst
+}
+
+func (ansiX923) Unpad(src []byte, blockSize int) ([]byte, error) {
+  n, err := lengthByte(src, blockSize)
+  if err != nil {
+    return nil, err
+  }
+
+  for _, b := range src[len(src)-n : len(src)-1] {
+    if b != 0 {
+      return nil, fmt.Errorf("%w: padding byte %#x should be 0x0", ErrInvalidPadding, b)
+    }
+  }
+  return src[:len(src)-n], nil
+}
+
+type iso7816 struct{}
+
+func (iso7816) Pad(src []byte, blockSize int) []byte {
+  n := paddingLength(len(src), blockSize)
+
+  // new bytes are already zero
+  dst := pad(src, n)
+  dst[len(src)] = 0x80
+  return dst
+}
+
+func (iso7816) Unpad(src []byte, blockSize int) ([]byte, error) {
+  if err := checkFullBlocks(src, blockSize); err != nil {
+    return nil, err
+  }
+
+  // padding is contained in the last block only
+  for i := len(src) - 1; i >= len(src)-blockSize; i-- {
+    switch src[i] {
+    case 0:
+      continue
+    case 0x80:
+      return src[:i], nil
+    default:
+      return nil, fmt.Errorf("%w: padding byte %#x should be 0x0 or 0x80", ErrInvalidPadding, src[i])
+    }
+  }
+  return nil, fmt.Errorf("%w: no 0x80 byte in last block", ErrInvalidPadding)
+}
+
+type iso10126 struct{}
+
+func (iso10126) Pad(src []byte, blockSize int) []byte {
+  lengthByteBlockSize(blockSize)
+  n := paddingLength(len(src), blockSize)
+
+  dst := pad(src, n)
+  if _, err := rand.Read(dst[len(src) : len(dst)-1]); err != nil {
+    panic("grypto/padding: failed to read random bytes: " + err.Error())
+  }
+  dst[len(dst)-1] = byte(n)
+  return dst
+}
+
+func (iso10126) Unpad(src []byte, blockSize int) ([]byte, error) {
+  // padding bytes are random, only the length can be validated
+  n, err := lengthByte(src, blockSize)
+  if err != nil {
+    return nil, err
+  }
+  return src[:len(src)-n], nil
+}
+
+type zeroPadding struct{}
+
+func (zeroPadding) Pad(src []byte, blockSize int) []byte {
+  n := paddingLength(len(src), blockSize)
+  if n == blockSize {
+    // input is already a multiple of the block size
+    n = 0
+  }
+
+  // new bytes are already zero
+  return pad(src, n)
+}
+
+func (zeroPadding) Unpad(src []byte, blockSize int) ([]byte, error) {
+  if len(src) == 0 {
+    return src, nil
+  }
+  if err := checkFullBlocks(src, blockSize); err != nil {
+    return nil, err
+  }
+
+  // padding is contained in the last block only
+  i := len(src)
+  for i > len(src)-blockSize && src[i-1] == 0 {
+    i--
+  }
+  return src[:i], nil
+}
@@ -0,0 +1,144 @@
+package block_test
+
+import (
+  "bytes"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/block"
+)
+
+var _ = Describe("Padding", func() {
+  testRoundTrip := func(padding block.Padding) {
+    for _, blockSize := range []int{1, 8, 16} {
+      for n := 0; n <= 2*blockSize+1; n++ {
+        src := bytes.Repeat([]byte{'a'}, n)
+
+        padded := padding.Pad(src, blockSize)
+        ExpectWithOffset(1, len(padded)%blockSize).To(BeZero(), "padded length should be full blocks")
+        ExpectWithOffset(1, padded[:n]).To(Equal(src), "padded input should start with src")
+
+        unpadded, err := padding.Unpad(padded, blockSize)
+        ExpectWithOffset(1, err).NotTo(HaveOccurred())
+        ExpectWithOffset(1, unpadded).To(Equal(src), "unpadded input should equal src")
+      }
+    }
+  }
+
+  testInvalid := func(padding block.Padding, src []byte, blockSize int, expectedErr error) {
+    _, err := padding.Unpad(src, blockSize)
+    ExpectWithOffset(1, err).To(MatchError(expectedErr))
+  }
+
+  It("should not modify the input", func() {
+    src := make([]byte, 3, 8)
+    for _, padding := range []block.Padding{block.PKCS7, block.ANSIX923, block.ISO7816, block.ISO10126} {
+      padding.Pad(src, 8)
+      Expect(src[:8]).To(Equal(make([]byte, 8)))
+    }
+  })
+
+  Describe("PKCS7", func() {
+    It("should pad correctly", func() {
+      Expect(block.PKCS7.Pad([]byte("abcde"), 8)).To(Equal([]byte("abcde\x03\x03\x03")))
+      Expect(block.PKCS7.Pad([]byte("abcdefgh"), 8)).To(Equal([]byte("abcdefgh\x08\x08\x08\x08\x08\x08\x08\x08")))
+    })
+
+    It("should remove padding correctly", func() {
+      testRoundTrip(block.PKCS7)
+    })
+
+    It("should reject invalid padding", func() {
+      testInvalid(block.PKCS7, nil, 8, block.ErrNotFullBlocks)
+      testInvalid(block.PKCS7, []byte("abcde\x03\x03"), 8, block.ErrNotFullBlocks)
+      testInvalid(block.PKCS7, []byte("abcdefg\x00"), 8, block.ErrInvalidPadding)
+      testInvalid(block.PKCS7, []byte("abcdefg\x09"), 8, block.ErrInvalidPadding)
+      testInvalid(block.PKCS7, []byte("abcde\x02\x03\x03"), 8, block.ErrInvalidPadding)
+    })
+
+    It("should panic on block sizes greater than 255", func() {
+      Expect(func() {
+        block.PKCS7.Pad(nil, 256)
+      }).To(Panic())
+    })
+  })
+
+  Describe("ANSIX923", func() {
+    It("should pad correctly", func() {
+      Expect(block.ANSIX923.Pad([]byte("abcde"), 8)).To(Equal([]byte("abcde\x00\x00\x03")))
+      Expect(block.ANSIX923.Pad([]byte("abcdefgh"), 8)).To(Equal([]byte("abcdefgh\x00\x00\x00\x00\x00\x00\x00\x08")))
+    })
+
+    It("should remove padding correctly", func() {
+      testRoundTrip(block.ANSIX923)
+    })
+
+    It("should reject invalid padding", func() {
+      testInvalid(block.ANSIX923, nil, 8, block.ErrNotFullBlocks)
+      testInvalid(block.ANSIX923, []byte("abcdefg\x00"), 8, block.ErrInvalidPadding)
+      testInvalid(block.ANSIX923, []byte("abcdefg\x09"), 8, block.ErrInvalidPadding)
+      testInvalid(block.ANSIX923, []byte("abcde\x01\x00\x03"), 8, block.ErrInvalidPadding)
+    })
+  })
+
+  Describe("ISO7816", func() {
+    It("should pad correctly", func() {
+      Expect(block.ISO7816.Pad([]byte("abcde"), 8)).To(Equal([]byte("abcde\x80\x00\x00")))
+      Expect(block.ISO7816.Pad([]byte("abcdefgh"), 8)).To(Equal([]byte("abcdefgh\x80\x00\x00\x00\x00\x00\x00\x00")))
+    })
+
+    It("should remove padding correctly", func() {
+      testRoundTrip(block.ISO7816)
+    })
+
+    It("should reject invalid padding", func() {
+      testInvalid(block.ISO7816, nil, 8, block.ErrNotFullBlocks)
+      testInvalid(block.ISO7816, []byte("abcdefg\x00"), 8, block.ErrInvalidPadding)
+      testInvalid(block.ISO7816, []byte("abcde\x80\x01\x00"), 8, block.ErrInvalidPadding)
+      // the padding must be contained in the last block
+      testInvalid(block.ISO7816, []byte("abcdefg\x80\x00\x00\x00\x00\x00\x00\x00\x00"), 8, block.ErrInvalidPadding)
+    })
+  })
+
+  Describe("ISO10126", func() {
+    It("should pad correctly", func() {
+      padded := block.ISO10126.Pad([]byte("abcde"), 8)
+      Expect(padded).To(HaveLen(8))
+      Expect(padded[:5]).To(Equal([]byte("abcde")))
+      Expect(padded[7]).To(Equal(byte(3)))
+    })
+
+    It("should remove padding correctly", func() {
+      testRoundTrip(block.ISO10126)
+    })
+
+    It("should reject invalid padding", func() {
+      testInvalid(block.ISO10126, nil, 8, block.ErrNotFullBlocks)
+      testInvalid(block.ISO10126, []byte("abcdefg\x00"), 8, block.ErrInvalidPadding)
+      testInvalid(block.ISO10126, []byte("abcdefg\x09"), 8, block.ErrInvalidPadding)
+    })
+  })
+
+  Describe("ZeroPadding", func() {
+    It("should pad correctly", func() {
+      Expect(block.ZeroPadding.Pad([]byte("abcde"), 8)).To(Equal([]byte("abcde\x00\x00\x00")))
+      Expect(block.ZeroPadding.Pad([]byte("abcdefgh"), 8)).To(Equal([]byte("abcdefgh")))
+      Expect(block.ZeroPadding.Pad(nil, 8)).To(BeEmpty())
+    })
+
+    It("should remove padding correctly", func() {
+      testRoundTrip(block.ZeroPadding)
+    })
+
+    It("should remove trailing zeros of the plaintext", func() {
+      unpadded, err := block.ZeroPadding.Unpad([]byte("abc\x00\x00\x00\x00\x00"), 8)
+      Expect(err).NotTo(HaveOccurred())
+      Expect(unpadded).To(Equal([]byte("abc")))
+    })
+
+    It("should reject invalid padding", func() {
+      testInvalid(block.ZeroPadding, []byte("abcde"), 8, block.ErrNotFullBlocks)
+    })
+  })
+})
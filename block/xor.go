@@ -0,0 +1,15 @@
+package block
+
+// xorBytes sets dst[i] = a[i] ^ b[i] for all i < n = min(len(a), len(b)) and returns n.
+// dst must be at least n bytes long.
+func xorBytes(dst, a, b []byte) int {
+  n := len(a)
+  if len(b) < n {
+    n = len(b)
+  }
+
+  for i := 0; i < n; i++ {
+    dst[i] = a[i] ^ b[i]
+  }
+  return n
+}
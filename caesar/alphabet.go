@@ -0,0 +1,168 @@
+package caesar
+
+import (
+  "bufio"
+  "errors"
+  "fmt"
+  "io"
+  "unicode"
+  "unicode/utf8"
+)
+
+// LatinAlphabet is the alphabet used by NewCipher.
+const LatinAlphabet = "abcdefghijklmnopqrstuvwxyz"
+
+// RuneCipher implements the Caesar Cipher for an arbitrary alphabet of unicode characters (runes).
+// In contrast to NewCipher, it doesn't operate on single bytes, so it can also be used for non-latin alphabets or
+// alphabets with multi-byte characters like German umlauts. As the encrypted character might be encoded with a
+// different number of bytes than the original one, it can't be used as a cipher.Block. Instead, it offers methods
+// for encrypting single runes and UTF-8 encoded streams.
+type RuneCipher struct {
+  alphabet []rune
+  // index maps each character to its position in the alphabet
+  index map[rune]int
+  key   int
+}
+
+// NewRuneCipher returns a new RuneCipher which implements the Caesar Cipher for the given ordered alphabet.
+// Characters of the alphabet are encrypted by replacing them by the `key`-th next character in the alphabet.
+// Like in NewCipher, the characters' case is kept, so the alphabet only needs to contain one case of each character:
+// characters in the other case are shifted by their position in the alphabet and converted back to their case
+// afterwards. If the resulting character doesn't have another case (e.g. the German ß), the case can't be kept and it
+// is returned as contained in the alphabet, so decrypting it yields the character in the alphabet's case.
+// Characters not contained in the alphabet (in either case) are not replaced. The alphabet must not be empty or
+// contain duplicate characters.
+func NewRuneCipher(key int, alphabet []rune) (*RuneCipher, error) {
+  if len(alphabet) == 0 {
+    return nil, errors.New("grypto/caesar: alphabet must not be empty")
+  }
+
+  index := make(map[rune]int, len(alphabet))
+  for i, r := range alphabet {
+    if _, ok := index[r]; ok {
+      return nil, fmt.Errorf("grypto/caesar: alphabet contains duplicate character %q", r)
+    }
+    index[r] = i
+  }
+
+  // avoid overflow and normalize key, modulo operation does not return a positive residue for a negative number
+  key %= len(alphabet)
+  if key < 0 {
+    key += len(alphabet)
+  }
+
+  return &RuneCipher{
+    alphabet: append([]rune(nil), alphabet...),
+    index:    index,
+    key:      key,
+  }, nil
+}
+
+// EncryptRune encrypts a single character.
+func (c *RuneCipher) EncryptRune(r rune) rune {
+  return c.substitute(c.key, r)
+}
+
+// DecryptRune decrypts a single character.
+func (c *RuneCipher) DecryptRune(r rune) rune {
+  return c.substitute(len(c.alphabet)-c.key, r)
+}
+
+func (c *RuneCipher) substitute(key int, r rune) rune {
+  if i, ok := c.index[r]; ok {
+    return c.alphabet[(i+key)%len(c.alphabet)]
+  }
+
+  // shift characters in the other case by their position in the alphabet and convert the result back, if possible
+  if other, ok := swapCase(r); ok {
+    if i, ok := c.index[other]; ok {
+      out := c.alphabet[(i+key)%len(c.alphabet)]
+      if swapped, ok := swapCase(out); ok {
+        return swapped
+      }
+      return out
+    }
+  }
+
+  return r
+}
+
+// swapCase returns the upper case of a lower case character and vice versa, if the case mapping can be reverted,
+// i.e. if converting the result back yields r again.
+func swapCase(r rune) (rune, bool) {
+  if upper := unicode.ToUpper(r); upper != r && unicode.ToLower(upper) == r {
+    return upper, true
+  }
+  if lower := unicode.ToLower(r); lower != r && unicode.ToUpper(lower) == r {
+    return lower, true
+  }
+  return r, false
+}
+
+// EncryptReader returns a io.Reader that encrypts the UTF-8 encoded text read from in.
+// Invalid UTF-8 sequences are not replaced.
+func (c *RuneCipher) EncryptReader(in io.Reader) io.Reader {
+  return newRuneReader(c.EncryptRune, in)
+}
+
+// DecryptReader returns a io.Reader that decrypts the UTF-8 encoded text read from in.
+// Invalid UTF-8 sequences are not replaced.
+func (c *RuneCipher) DecryptReader(in io.Reader) io.Reader {
+  return newRuneReader(c.DecryptRune, in)
+}
+
+type runeReader struct {
+  in    *bufio.Reader
+  crypt func(rune) rune
+
+  // out holds encoded output, that didn't fit into the buffer given to Read
+  out []byte
+  enc [utf8.UTFMax]byte
+}
+
+func newRuneReader(crypt func(rune) rune, in io.Reader) *runeReader {
+  return &runeReader{
+    in:    bufio.NewReader(in),
+    crypt: crypt,
+  }
+}
+
+// Read implements io.Reader.
+func (r *runeReader) Read(p []byte) (int, error) {
+  n := 0
+  for n < len(p) {
+    // return output left over from previous characters first
+    if len(r.out) > 0 {
+      m := copy(p[n:], r.out)
+      r.out = r.out[m:]
+      n += m
+      continue
+    }
+
+    // don't block for filling up p entirely
+    if n > 0 && r.in.Buffered() == 0 {
+      break
+    }
+
+    in, size, err := r.in.ReadRune()
+    if err != nil {
+      if n > 0 {
+        // return error on next read
+        return n, nil
+      }
+      return 0, err
+    }
+
+    if in == utf8.RuneError && size == 1 {
+      // invalid UTF-8 sequence, pass through the original byte
+      _ = r.in.UnreadRune()
+      r.enc[0], _ = r.in.ReadByte()
+      r.out = r.enc[:1]
+      continue
+    }
+
+    r.out = r.enc[:utf8.EncodeRune(r.enc[:], r.crypt(in))]
+  }
+
+  return n, nil
+}
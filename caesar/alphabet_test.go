@@ -0,0 +1,110 @@
+package caesar_test
+
+import (
+  "bytes"
+  "io/ioutil"
+  "testing/iotest"
+  "unicode"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/block"
+  "github.com/timebertt/grypto/caesar"
+)
+
+var _ = Describe("RuneCipher", func() {
+  const germanAlphabet = "abcdefghijklmnopqrstuvwxyzäöüß"
+
+  newRuneCipher := func(key int, alphabet string) *caesar.RuneCipher {
+    c, err := caesar.NewRuneCipher(key, []rune(alphabet))
+    ExpectWithOffset(1, err).NotTo(HaveOccurred())
+    return c
+  }
+
+  It("should reject invalid alphabets", func() {
+    _, err := caesar.NewRuneCipher(1, nil)
+    Expect(err).To(HaveOccurred())
+    _, err = caesar.NewRuneCipher(1, []rune("abca"))
+    Expect(err).To(HaveOccurred())
+  })
+
+  It("should encrypt single runes", func() {
+    c := newRuneCipher(1, germanAlphabet)
+    Expect(c.EncryptRune('a')).To(Equal('b'))
+    Expect(c.EncryptRune('z')).To(Equal('ä'))
+    Expect(c.EncryptRune('Z')).To(Equal('Ä'))
+    Expect(c.EncryptRune('ß')).To(Equal('a'))
+    Expect(c.EncryptRune('!')).To(Equal('!'))
+
+    Expect(c.DecryptRune('a')).To(Equal('ß'))
+    Expect(c.DecryptRune('Ä')).To(Equal('Z'))
+  })
+
+  It("should keep the case of alphabets given in upper case", func() {
+    c := newRuneCipher(2, "ΑΒΓΔΕΖΗΘΙΚΛΜΝΞΟΠΡΣΤΥΦΧΨΩ")
+    Expect(c.EncryptRune('Α')).To(Equal('Γ'))
+    Expect(c.EncryptRune('α')).To(Equal('γ'))
+    Expect(c.EncryptRune('ω')).To(Equal('β'))
+  })
+
+  It("should shift both cases by the same key", func() {
+    c := newRuneCipher(29, germanAlphabet)
+    Expect(c.EncryptRune('b')).To(Equal('a'))
+    Expect(c.EncryptRune('B')).To(Equal('A'))
+    Expect(c.EncryptRune('Ü')).To(Equal('Ö'))
+    // ß has no upper case, so the case of A can't be kept
+    Expect(c.EncryptRune('a')).To(Equal('ß'))
+    Expect(c.EncryptRune('A')).To(Equal('ß'))
+    Expect(c.DecryptRune('ß')).To(Equal('a'))
+
+    for key := -31; key <= 31; key++ {
+      c := newRuneCipher(key, germanAlphabet)
+      for _, r := range "abcßüÄÖÜABCZ" {
+        expected := r
+        if c.EncryptRune(r) == 'ß' {
+          expected = unicode.ToLower(r)
+        }
+        Expect(c.DecryptRune(c.EncryptRune(r))).To(Equal(expected), "key %d: %q", key, r)
+      }
+    }
+  })
+
+  It("should normalize keys", func() {
+    Expect(newRuneCipher(31, germanAlphabet).EncryptRune('a')).To(Equal('b'))
+    Expect(newRuneCipher(-1, germanAlphabet).EncryptRune('a')).To(Equal('ß'))
+  })
+
+  It("should encrypt and decrypt multi-byte characters in streams", func() {
+    c := newRuneCipher(1, germanAlphabet)
+
+    out, err := ioutil.ReadAll(c.EncryptReader(iotest.OneByteReader(bytes.NewBufferString("Grüße aus Zürich!"))))
+    Expect(err).NotTo(HaveOccurred())
+    Expect(string(out)).To(Equal("Hsßaf bvt Äßsjdi!"))
+
+    out, err = ioutil.ReadAll(iotest.OneByteReader(c.DecryptReader(bytes.NewBufferString(string(out)))))
+    Expect(err).NotTo(HaveOccurred())
+    Expect(string(out)).To(Equal("Grüße aus Zürich!"))
+  })
+
+  It("should pass through invalid UTF-8 sequences", func() {
+    c := newRuneCipher(1, germanAlphabet)
+
+    out, err := ioutil.ReadAll(c.EncryptReader(bytes.NewBuffer([]byte{'a', 0xff, 0xc3, 'b'})))
+    Expect(err).NotTo(HaveOccurred())
+    Expect(out).To(Equal([]byte{'b', 0xff, 0xc3, 'c'}))
+  })
+
+  It("should behave like NewCipher for the latin alphabet", func() {
+    in := "Caesar Cipher is old but not very secure"
+    for key := -30; key <= 30; key++ {
+      expected, err := ioutil.ReadAll(block.NewBlockModeReader(block.NewECBEncrypter(caesar.NewCipher(key)),
+        bytes.NewBufferString(in)))
+      Expect(err).NotTo(HaveOccurred())
+
+      out, err := ioutil.ReadAll(newRuneCipher(key, caesar.LatinAlphabet).EncryptReader(bytes.NewBufferString(in)))
+      Expect(err).NotTo(HaveOccurred())
+      Expect(out).To(Equal(expected))
+    }
+  })
+})
@@ -0,0 +1,106 @@
+package caesar
+
+import "sort"
+
+// Language is a model of a natural language used for cryptanalysis, which holds the relative frequencies of the
+// letters A-Z in typical texts of the language (in percent).
+// See https://en.wikipedia.org/wiki/Letter_frequency
+type Language struct {
+  Name        string
+  Frequencies [modulus]float64
+}
+
+var (
+  // English is the letter frequency model of the English language.
+  English = Language{
+    Name: "English",
+    Frequencies: [modulus]float64{
+      8.167, 1.492, 2.782, 4.253, 12.702, 2.228, 2.015, 6.094, 6.966, 0.153, 0.772, 4.025, 2.406,
+      6.749, 7.507, 1.929, 0.095, 5.987, 6.327, 9.056, 2.758, 0.978, 2.360, 0.150, 1.974, 0.074,
+    },
+  }
+  // German is the letter frequency model of the German language (umlauts and ß are not included).
+  German = Language{
+    Name: "German",
+    Frequencies: [modulus]float64{
+      6.516, 1.886, 2.732, 5.076, 16.396, 1.656, 3.009, 4.577, 6.550, 0.268, 1.417, 3.437, 2.534,
+      9.776, 2.594, 0.670, 0.018, 7.003, 7.270, 6.154, 4.166, 0.846, 1.921, 0.034, 0.039, 1.134,
+    },
+  }
+)
+
+// Candidate is a possible key for a given ciphertext found by Crack.
+type Candidate struct {
+  // Key is the key, that the ciphertext was encrypted with (if this candidate is correct).
+  Key int
+  // ChiSquared is the chi-squared statistic comparing the letter frequencies of Plaintext to the expected
+  // frequencies of the language. The lower the value, the more Plaintext resembles a text of the language.
+  ChiSquared float64
+  // Plaintext is the ciphertext decrypted with Key.
+  Plaintext []byte
+}
+
+// Crack recovers the key of a ciphertext encrypted with the Caesar Cipher by brute force and frequency analysis.
+// As there are only 26 different keys, the Caesar Cipher can easily be broken by trying all of them. To find the
+// correct key automatically, the letter frequencies of each candidate plaintext are compared to the typical letter
+// frequencies of the given language using the chi-squared statistic:
+//   χ² = Σ (observed - expected)² / expected
+// Crack returns all 26 candidates ordered by their score, so the most probable key comes first.
+// Frequency analysis works best for longer ciphertexts, short texts might not be cracked correctly.
+// See https://en.wikipedia.org/wiki/Caesar_cipher#Breaking_the_cipher
+func Crack(ciphertext []byte, language Language) []Candidate {
+  candidates := make([]Candidate, modulus)
+
+  for key := 0; key < modulus; key++ {
+    c := NewCipher(key)
+    plaintext := make([]byte, len(ciphertext))
+    for i := range ciphertext {
+      c.Decrypt(plaintext[i:i+1], ciphertext[i:i+1])
+    }
+
+    candidates[key] = Candidate{
+      Key:        key,
+      ChiSquared: chiSquared(plaintext, language),
+      Plaintext:  plaintext,
+    }
+  }
+
+  sort.SliceStable(candidates, func(i, j int) bool {
+    return candidates[i].ChiSquared < candidates[j].ChiSquared
+  })
+  return candidates
+}
+
+// chiSquared calculates the chi-squared statistic of the letter counts of text compared to the expected counts
+// according to the given language.
+func chiSquared(text []byte, language Language) float64 {
+  var (
+    counts [modulus]int
+    total  int
+  )
+
+  for _, b := range text {
+    in := rune(b)
+    switch {
+    case in >= minUpper && in <= maxUpper:
+      counts[in-minUpper]++
+    case in >= minLower && in <= maxLower:
+      counts[in-minLower]++
+    default:
+      continue
+    }
+    total++
+  }
+
+  var chi float64
+  if total == 0 {
+    return chi
+  }
+
+  for i, count := range counts {
+    expected := float64(total) * language.Frequencies[i] / 100
+    diff := float64(count) - expected
+    chi += diff * diff / expected
+  }
+  return chi
+}
@@ -0,0 +1,59 @@
+package caesar_test
+
+import (
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/caesar"
+)
+
+var _ = Describe("Crack", func() {
+  encrypt := func(key int, in string) []byte {
+    c := caesar.NewCipher(key)
+    out := make([]byte, len(in))
+    for i := range in {
+      c.Encrypt(out[i:i+1], []byte(in[i:i+1]))
+    }
+    return out
+  }
+
+  It("should return all keys ordered by score", func() {
+    candidates := caesar.Crack(encrypt(3, "Caesar Cipher is old but not very secure"), caesar.English)
+    Expect(candidates).To(HaveLen(26))
+
+    keys := map[int]bool{}
+    for i, c := range candidates {
+      keys[c.Key] = true
+      if i > 0 {
+        Expect(c.ChiSquared).To(BeNumerically(">=", candidates[i-1].ChiSquared))
+      }
+    }
+    Expect(keys).To(HaveLen(26))
+  })
+
+  It("should recover the key of an English text", func() {
+    plaintext := "The quick brown fox jumps over the lazy dog. It was the best of times, it was the worst of times."
+    for key := 0; key < 26; key++ {
+      candidates := caesar.Crack(encrypt(key, plaintext), caesar.English)
+      Expect(candidates[0].Key).To(Equal(key))
+      Expect(string(candidates[0].Plaintext)).To(Equal(plaintext))
+    }
+  })
+
+  It("should recover the key of a German text", func() {
+    plaintext := "Franz jagt im komplett verwahrlosten Taxi quer durch Bayern. Die Sonne scheint und es ist ein " +
+      "schoener Tag in der Stadt."
+    for key := 0; key < 26; key++ {
+      candidates := caesar.Crack(encrypt(key, plaintext), caesar.German)
+      Expect(candidates[0].Key).To(Equal(key))
+      Expect(string(candidates[0].Plaintext)).To(Equal(plaintext))
+    }
+  })
+
+  It("should handle ciphertext without latin letters", func() {
+    candidates := caesar.Crack([]byte("1234 !?"), caesar.English)
+    Expect(candidates).To(HaveLen(26))
+    Expect(candidates[0].ChiSquared).To(BeZero())
+    Expect(string(candidates[0].Plaintext)).To(Equal("1234 !?"))
+  })
+})
@@ -1,9 +1,12 @@
 package euclid
 
+import "math/big"
+
 // GreatestCommonDivisorExtended also calculates the greatest common divisor (gcd) of two integers but additionally
-// calculates two integers a and b, such that
+// calculates two integers x and y, such that
 //   gcd(a, b) = x*a + y*b
-// If gcd(a, b) = 1, y is b's multiplicative inverse in ℤₐ (y * b ≡ 1 mod a).
+// If gcd(a, b) = 1, x is a's multiplicative inverse in ℤ_b (x * a ≡ 1 mod b) and y is b's multiplicative inverse in ℤₐ
+// (y * b ≡ 1 mod a). See modular.Inverse64 for calculating normalized inverses.
 // See: https://en.wikipedia.org/wiki/Extended_Euclidean_algorithm
 func GreatestCommonDivisorExtended(a, b int) (gcd, x, y int) {
   if a < 0 || b < 0 {
@@ -27,3 +30,29 @@ func extendedEuclid(a, b int) (gcd, x, y int) {
   }
   return abs(a), x0, y0
 }
+
+// GreatestCommonDivisorExtendedBig is like GreatestCommonDivisorExtended but for arbitrarily large integers.
+func GreatestCommonDivisorExtendedBig(a, b *big.Int) (gcd, x, y *big.Int) {
+  if a.Sign() < 0 || b.Sign() < 0 {
+    panic("input may not be negative")
+  }
+
+  var (
+    r0, r1 = new(big.Int).Set(a), new(big.Int).Set(b)
+    x0, x1 = big.NewInt(1), big.NewInt(0)
+    y0, y1 = big.NewInt(0), big.NewInt(1)
+    q, t   = new(big.Int), new(big.Int)
+  )
+
+  for r1.Sign() != 0 {
+    q.QuoRem(r0, r1, t)
+    r0, r1, t = r1, t, r0
+    // x0, x1 = x1, x0-q*x1
+    x0.Sub(x0, t.Mul(q, x1))
+    x0, x1 = x1, x0
+    // y0, y1 = y1, y0-q*y1
+    y0.Sub(y0, t.Mul(q, y1))
+    y0, y1 = y1, y0
+  }
+  return r0.Abs(r0), x0, y0
+}
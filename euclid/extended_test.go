@@ -1,6 +1,8 @@
 package euclid_test
 
 import (
+  "math/big"
+
   . "github.com/onsi/ginkgo"
   . "github.com/onsi/gomega"
 
@@ -27,3 +29,38 @@ var _ = Describe("#GreatestCommonDivisorExtended", func() {
     test(3689, 3519, 17, -62, 65)
   })
 })
+
+var _ = Describe("#GreatestCommonDivisorExtendedBig", func() {
+  It("should panic on negative inputs", func() {
+    Expect(func() {
+      euclid.GreatestCommonDivisorExtendedBig(big.NewInt(-1), big.NewInt(1))
+    }).To(Panic())
+    Expect(func() {
+      euclid.GreatestCommonDivisorExtendedBig(big.NewInt(1), big.NewInt(-1))
+    }).To(Panic())
+  })
+
+  It("should return the same results as GreatestCommonDivisorExtended", func() {
+    for a := 0; a <= 100; a++ {
+      for b := 0; b <= 100; b++ {
+        expectedGCD, expectedX, expectedY := euclid.GreatestCommonDivisorExtended(a, b)
+        gcd, x, y := euclid.GreatestCommonDivisorExtendedBig(big.NewInt(int64(a)), big.NewInt(int64(b)))
+        Expect(gcd.Int64()).To(Equal(int64(expectedGCD)), "gcd(%d, %d)", a, b)
+        Expect(x.Int64()).To(Equal(int64(expectedX)), "x for gcd(%d, %d)", a, b)
+        Expect(y.Int64()).To(Equal(int64(expectedY)), "y for gcd(%d, %d)", a, b)
+      }
+    }
+  })
+
+  It("should correctly calculate gcd and linear combination of large integers", func() {
+    a, _ := new(big.Int).SetString("170141183460469231731687303715884105727", 10) // 2^127-1
+    b, _ := new(big.Int).SetString("618970019642690137449562111", 10)             // 2^89-1
+
+    gcd, x, y := euclid.GreatestCommonDivisorExtendedBig(a, b)
+    Expect(gcd).To(Equal(big.NewInt(1)))
+
+    // gcd = x*a + y*b
+    sum := new(big.Int).Add(new(big.Int).Mul(x, a), new(big.Int).Mul(y, b))
+    Expect(sum).To(Equal(gcd))
+  })
+})
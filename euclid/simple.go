@@ -1,5 +1,7 @@
 package euclid
 
+import "math/big"
+
 // GreatestCommonDivisor calculates the greatest common divisor (gcd) of two integers using the simple form of Euclid's
 // algorithm. It is a fairly efficient algorithm based on the following two cases:
 //   gcd(a, 0) = 0
@@ -9,6 +11,20 @@ func GreatestCommonDivisor(a, b int) int {
   return euclid(a, b)
 }
 
+// GreatestCommonDivisorBig is like GreatestCommonDivisor but for arbitrarily large integers.
+func GreatestCommonDivisorBig(a, b *big.Int) *big.Int {
+  var (
+    x = new(big.Int).Set(a)
+    y = new(big.Int).Set(b)
+  )
+
+  for y.Sign() != 0 {
+    x.Rem(x, y)
+    x, y = y, x
+  }
+  return x.Abs(x)
+}
+
 func euclid(a, b int) int {
   for b != 0 {
     a, b = b, a%b
@@ -1,6 +1,8 @@
 package euclid_test
 
 import (
+  "math/big"
+
   . "github.com/onsi/ginkgo"
   . "github.com/onsi/gomega"
 
@@ -27,3 +29,23 @@ var _ = Describe("#GreatestCommonDivisor", func() {
     test(3689, 3519, 17)
   })
 })
+
+var _ = Describe("#GreatestCommonDivisorBig", func() {
+  It("should return the same results as GreatestCommonDivisor", func() {
+    for a := -50; a <= 50; a++ {
+      for b := -50; b <= 50; b++ {
+        gcd := euclid.GreatestCommonDivisorBig(big.NewInt(int64(a)), big.NewInt(int64(b)))
+        Expect(gcd.Int64()).To(Equal(int64(euclid.GreatestCommonDivisor(a, b))), "gcd(%d, %d)", a, b)
+      }
+    }
+  })
+
+  It("should correctly calculate gcd of large integers", func() {
+    a, _ := new(big.Int).SetString("170141183460469231731687303715884105727", 10) // 2^127-1
+    b := new(big.Int).Mul(a, big.NewInt(3))
+    c := new(big.Int).Mul(a, big.NewInt(5))
+
+    Expect(euclid.GreatestCommonDivisorBig(b, c)).To(Equal(a))
+    Expect(euclid.GreatestCommonDivisorBig(a, big.NewInt(1<<62))).To(Equal(big.NewInt(1)))
+  })
+})
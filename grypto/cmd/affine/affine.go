@@ -0,0 +1,121 @@
+package affine
+
+import (
+  "bytes"
+  "crypto/cipher"
+  "fmt"
+  "io"
+  "os"
+  "strconv"
+  "strings"
+
+  "github.com/spf13/cobra"
+
+  "github.com/timebertt/grypto/affine"
+  "github.com/timebertt/grypto/block"
+  "github.com/timebertt/grypto/grypto/options"
+)
+
+const (
+  CipherName = "Affine Cipher"
+)
+
+func NewCommand() *cobra.Command {
+  var (
+    input = &options.Input{}
+    key   = &options.Key{}
+
+    a, b int
+  )
+
+  cmd := &cobra.Command{
+    Use:   "affine",
+    Short: "Use the " + CipherName + " for encryption and decryption",
+    Long: `The ` + CipherName + ` is a substituting block cipher operating on blocks of length 1 (single bytes) and a
+generalization of the Caesar Cipher. Latin characters are encrypted by mapping their position x in the alphabet
+to the character at position a*x + b mod 26. The characters' case is kept and non-latin characters are not replaced.
+The key is given as a pair "a,b" (e.g. '-K 5,8'), where a must be coprime to 26.
+See: https://en.wikipedia.org/wiki/Affine_cipher`,
+    PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+      if err := input.Complete(cmd, args); err != nil {
+        return err
+      }
+      if err := key.Complete(cmd, args); err != nil {
+        return err
+      }
+
+      keyInput := &bytes.Buffer{}
+      if _, err := io.Copy(keyInput, key.In); err != nil {
+        return fmt.Errorf("error reading input: %w", err)
+      }
+      if keyInput.Len() == 0 {
+        return fmt.Errorf("given key is empty")
+      }
+
+      var err error
+      a, b, err = parseKey(strings.TrimSuffix(keyInput.String(), "\n"))
+      if err != nil {
+        return err
+      }
+
+      cmd.SilenceErrors = true
+      cmd.SilenceUsage = true
+
+      return nil
+    },
+    PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+      if err := input.PostRun(cmd, args); err != nil {
+        return err
+      }
+      if err := key.PostRun(cmd, args); err != nil {
+        return err
+      }
+      return nil
+    },
+  }
+
+  options.AddEncryptDecryptSubcommands(cmd, CipherName, func(cmd *cobra.Command, direction options.Direction, args []string) error {
+    return runAffine(direction, a, b, input.In)
+  })
+
+  input.AddFlags(cmd.PersistentFlags())
+  key.AddFlags(cmd.PersistentFlags())
+
+  return cmd
+}
+
+// parseKey parses a key pair given as "a,b".
+func parseKey(key string) (a, b int, err error) {
+  parts := strings.Split(key, ",")
+  if len(parts) != 2 {
+    return 0, 0, fmt.Errorf("given key is not a pair of ints (a,b): %q", key)
+  }
+
+  a, err = strconv.Atoi(strings.TrimSpace(parts[0]))
+  if err != nil {
+    return 0, 0, fmt.Errorf("first part of given key is not an int: %w", err)
+  }
+  b, err = strconv.Atoi(strings.TrimSpace(parts[1]))
+  if err != nil {
+    return 0, 0, fmt.Errorf("second part of given key is not an int: %w", err)
+  }
+
+  return a, b, nil
+}
+
+func runAffine(direction options.Direction, a, b int, input io.Reader) error {
+  c, err := affine.NewCipher(a, b)
+  if err != nil {
+    return err
+  }
+
+  var blockMode cipher.BlockMode
+  if direction == options.Decrypt {
+    blockMode = block.NewECBDecrypter(c)
+  } else {
+    blockMode = block.NewECBEncrypter(c)
+  }
+
+  _, err = io.Copy(os.Stdout, block.NewBlockModeReader(blockMode, input))
+  return err
+}
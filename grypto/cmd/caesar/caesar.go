@@ -22,10 +22,9 @@ const (
 
 func NewCommand() *cobra.Command {
   var (
-    input = &options.Input{}
-    key   = &options.Key{}
-
-    parsedKey int
+    input    = &options.Input{}
+    key      = &options.Key{}
+    alphabet string
   )
 
   cmd := &cobra.Command{
@@ -34,28 +33,13 @@ func NewCommand() *cobra.Command {
     Long: CipherName + ` is is a substituting block cipher operating on blocks of length 1 (single bytes).
 Latin characters are encrypted by replacing them by the key-th next character in the alphabet.
 The characters' case is kept and non-latin characters are not replaced.
+A custom alphabet (e.g. including umlauts) can be given via --alphabet, in which case the input is treated as UTF-8
+encoded text and characters of the given alphabet are replaced instead.
 See: https://en.wikipedia.org/wiki/Caesar_cipher`,
     PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
       if err := input.Complete(cmd, args); err != nil {
         return err
       }
-      if err := key.Complete(cmd, args); err != nil {
-        return err
-      }
-
-      keyInput := &bytes.Buffer{}
-      if _, err := io.Copy(keyInput, key.In); err != nil {
-        return fmt.Errorf("error reading input: %w", err)
-      }
-      if keyInput.Len() == 0 {
-        return fmt.Errorf("given key is empty")
-      }
-
-      var err error
-      parsedKey, err = strconv.Atoi(strings.TrimSuffix(keyInput.String(), "\n"))
-      if err != nil {
-        return fmt.Errorf("given key is not an int: %w", err)
-      }
 
       cmd.SilenceErrors = true
       cmd.SilenceUsage = true
@@ -74,16 +58,49 @@ See: https://en.wikipedia.org/wiki/Caesar_cipher`,
   }
 
   options.AddEncryptDecryptSubcommands(cmd, CipherName, func(cmd *cobra.Command, direction options.Direction, args []string) error {
-    return runCaesar(direction, parsedKey, input.In)
+    parsedKey, err := parseKey(key, cmd, args)
+    if err != nil {
+      return err
+    }
+    return runCaesar(direction, parsedKey, alphabet, input.In)
   })
 
+  // the alphabet is only used for encryption and decryption, crack always operates on the latin alphabet
+  for _, c := range cmd.Commands() {
+    c.Flags().StringVar(&alphabet, "alphabet", "",
+      "ordered alphabet to use for encryption and decryption (defaults to the latin alphabet operating on single bytes)")
+  }
+
+  cmd.AddCommand(newCrackCommand(input))
+
   input.AddFlags(cmd.PersistentFlags())
   key.AddFlags(cmd.PersistentFlags())
 
   return cmd
 }
 
-func runCaesar(direction options.Direction, key int, input io.Reader) (err error) {
+// parseKey reads the Caesar key from the given key option. The key is only needed for encryption and decryption.
+func parseKey(key *options.Key, cmd *cobra.Command, args []string) (int, error) {
+  if err := key.Complete(cmd, args); err != nil {
+    return 0, err
+  }
+
+  keyInput := &bytes.Buffer{}
+  if _, err := io.Copy(keyInput, key.In); err != nil {
+    return 0, fmt.Errorf("error reading input: %w", err)
+  }
+  if keyInput.Len() == 0 {
+    return 0, fmt.Errorf("given key is empty")
+  }
+
+  parsedKey, err := strconv.Atoi(strings.TrimSuffix(keyInput.String(), "\n"))
+  if err != nil {
+    return 0, fmt.Errorf("given key is not an int: %w", err)
+  }
+  return parsedKey, nil
+}
+
+func runCaesar(direction options.Direction, key int, alphabet string, input io.Reader) (err error) {
   defer func() {
     if p := recover(); p != nil {
       if e, ok := p.(error); ok {
@@ -92,6 +109,10 @@ func runCaesar(direction options.Direction, key int, input io.Reader) (err error
     }
   }()
 
+  if alphabet != "" {
+    return runRuneCaesar(direction, key, alphabet, input)
+  }
+
   var blockMode cipher.BlockMode
   if direction == options.Decrypt {
     blockMode = block.NewECBDecrypter(caesar.NewCipher(key))
@@ -102,3 +123,20 @@ func runCaesar(direction options.Direction, key int, input io.Reader) (err error
   _, err = io.Copy(os.Stdout, block.NewBlockModeReader(blockMode, input))
   return err
 }
+
+func runRuneCaesar(direction options.Direction, key int, alphabet string, input io.Reader) error {
+  c, err := caesar.NewRuneCipher(key, []rune(alphabet))
+  if err != nil {
+    return err
+  }
+
+  var reader io.Reader
+  if direction == options.Decrypt {
+    reader = c.DecryptReader(input)
+  } else {
+    reader = c.EncryptReader(input)
+  }
+
+  _, err = io.Copy(os.Stdout, reader)
+  return err
+}
@@ -0,0 +1,82 @@
+package caesar
+
+import (
+  "fmt"
+  "io/ioutil"
+  "os"
+  "strings"
+  "text/tabwriter"
+
+  "github.com/spf13/cobra"
+
+  "github.com/timebertt/grypto/caesar"
+  "github.com/timebertt/grypto/grypto/options"
+)
+
+// previewLength is the maximum number of characters printed for each candidate plaintext.
+const previewLength = 60
+
+var languages = map[string]caesar.Language{
+  "en": caesar.English,
+  "de": caesar.German,
+}
+
+func newCrackCommand(input *options.Input) *cobra.Command {
+  var (
+    language string
+    top      int
+  )
+
+  cmd := &cobra.Command{
+    Use:   "crack",
+    Short: "Crack ciphertext encrypted with the " + CipherName + " without knowing the key",
+    Long: `crack recovers the key of a ciphertext encrypted with the ` + CipherName + ` by brute force and frequency
+analysis. As there are only 26 different keys, the ` + CipherName + ` can easily be broken by trying all of them.
+To find the correct key automatically, the letter frequencies of each candidate plaintext are compared to the
+typical letter frequencies of the given language using the chi-squared statistic.
+The candidate keys are printed ordered by their score, so the most probable key comes first.
+See: https://en.wikipedia.org/wiki/Caesar_cipher#Breaking_the_cipher`,
+    Args: cobra.NoArgs,
+    RunE: func(cmd *cobra.Command, args []string) error {
+      lang, ok := languages[language]
+      if !ok {
+        return fmt.Errorf("unsupported language %q, supported languages are 'en' and 'de'", language)
+      }
+      return runCrack(input, lang, top)
+    },
+  }
+
+  cmd.Flags().StringVarP(&language, "language", "l", "en", "language model used for frequency analysis (en or de)")
+  cmd.Flags().IntVarP(&top, "top", "n", 26, "number of candidate keys to print")
+
+  return cmd
+}
+
+func runCrack(input *options.Input, language caesar.Language, top int) error {
+  ciphertext, err := ioutil.ReadAll(input.In)
+  if err != nil {
+    return fmt.Errorf("error reading input: %w", err)
+  }
+
+  candidates := caesar.Crack(ciphertext, language)
+  if top >= 0 && top < len(candidates) {
+    candidates = candidates[:top]
+  }
+
+  w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+  fmt.Fprintf(w, "RANK\tKEY\tCHI-SQUARED (%s)\tPLAINTEXT\n", language.Name)
+  for i, c := range candidates {
+    fmt.Fprintf(w, "%d\t%d\t%.2f\t%s\n", i+1, c.Key, c.ChiSquared, preview(c.Plaintext))
+  }
+  return w.Flush()
+}
+
+// preview returns the beginning of the given text on a single line.
+func preview(text []byte) string {
+  s := strings.Join(strings.Fields(string(text)), " ")
+  // cut by characters instead of bytes, so that multi-byte characters are not split
+  if r := []rune(s); len(r) > previewLength {
+    s = string(r[:previewLength]) + "..."
+  }
+  return s
+}
@@ -0,0 +1,101 @@
+package crt
+
+import (
+  "fmt"
+  "strconv"
+  "strings"
+
+  "github.com/spf13/cobra"
+
+  "github.com/timebertt/grypto/modular"
+)
+
+func NewCommand() *cobra.Command {
+  var congruences []modular.Congruence
+
+  cmd := &cobra.Command{
+    Use:     "crt [residue:modulus]...",
+    Aliases: []string{"chinese-remainder"},
+    Short:   "Solve a system of congruences using the chinese remainder theorem",
+    Long: `crt solves a system of congruences x ≡ rᵢ mod mᵢ using the chinese remainder theorem. Each congruence is
+given as an argument of the form residue:modulus, e.g.
+  grypto crt 2:3 3:5 2:7
+solves x ≡ 2 mod 3, x ≡ 3 mod 5 and x ≡ 2 mod 7. It prints all intermediate steps and the solution as a single
+congruence x ≡ r mod m, where m is the least common multiple of all moduli.
+
+If the moduli are pairwise coprime, the chinese remainder theorem guarantees that there is exactly one solution
+modulo m = m₁ * ... * mₖ. Otherwise, the system only has a solution if rᵢ ≡ rⱼ mod gcd(mᵢ, mⱼ) for all i, j.
+
+The congruences are combined one after another: x ≡ r₁ mod m₁ and x ≡ r₂ mod m₂ are solved by x = r₁ + m₁*t,
+where t solves m₁*t ≡ r₂ - r₁ mod m₂, which is done using the extended euclidean algorithm.
+See https://en.wikipedia.org/wiki/Chinese_remainder_theorem.`,
+    Args: cobra.MinimumNArgs(1),
+    PreRunE: func(cmd *cobra.Command, args []string) error {
+      var err error
+      congruences, err = parseCongruences(args)
+      if err != nil {
+        return err
+      }
+
+      cmd.SilenceErrors = true
+      cmd.SilenceUsage = true
+
+      return nil
+    },
+    RunE: func(cmd *cobra.Command, args []string) error {
+      return runCRT(congruences)
+    },
+  }
+
+  return cmd
+}
+
+func parseCongruences(args []string) ([]modular.Congruence, error) {
+  congruences := make([]modular.Congruence, len(args))
+
+  for i, arg := range args {
+    parts := strings.Split(arg, ":")
+    if len(parts) != 2 {
+      return nil, fmt.Errorf("congruence must be given as residue:modulus: %q", arg)
+    }
+
+    residue, err := strconv.ParseInt(parts[0], 10, 64)
+    if err != nil {
+      return nil, fmt.Errorf("residue is not an int64: %q", arg)
+    }
+    modulus, err := strconv.ParseInt(parts[1], 10, 64)
+    if err != nil {
+      return nil, fmt.Errorf("modulus is not an int64: %q", arg)
+    }
+
+    congruences[i] = modular.Congruence{Residue: residue, Modulus: modulus}
+  }
+
+  return congruences, nil
+}
+
+func runCRT(congruences []modular.Congruence) (err error) {
+  defer func() {
+    if p := recover(); p != nil {
+      if e, ok := p.(error); ok {
+        err = e
+      }
+      if e, ok := p.(string); ok {
+        err = fmt.Errorf(e)
+      }
+    }
+  }()
+
+  trace := func(format string, a ...interface{}) {
+    fmt.Printf(format+"\n", a...)
+  }
+
+  solution, err := modular.CRTTrace(congruences, trace)
+  if err != nil {
+    return err
+  }
+
+  fmt.Printf("=> %s\n", solution)
+
+  return nil
+}
@@ -3,26 +3,113 @@ package dlog
 import (
   "fmt"
   "math"
+  "math/big"
   "strconv"
 
   "github.com/spf13/cobra"
 
+  "github.com/timebertt/grypto/grypto/options"
   "github.com/timebertt/grypto/modular"
 )
 
+const (
+  methodEnumeration       = "enumeration"
+  methodBabyStepGiantStep = "bsgs"
+  methodPollardRho        = "rho"
+  methodPohligHellman     = "pohlig-hellman"
+  methodIndexCalculus     = "index-calculus"
+)
+
+type dlogFunc func(x, base, mod int32, trace modular.TraceFunc) (int32, bool)
+
+var (
+  methods = []string{
+    methodEnumeration, methodBabyStepGiantStep, methodPollardRho, methodPohligHellman, methodIndexCalculus,
+  }
+
+  dlogFuncs = map[string]dlogFunc{
+    methodEnumeration:       withoutTrace(modular.DLog32),
+    methodBabyStepGiantStep: withoutTrace(modular.DLogBabyStepGiantStep32),
+    methodPollardRho:        withoutTrace(modular.DLogPollardRho32),
+    methodPohligHellman:     modular.DLogPohligHellmanTrace32,
+    methodIndexCalculus:     dlogIndexCalculus,
+  }
+)
+
+// dlogBigFuncs holds the methods supporting arbitrary-precision arithmetic.
+var dlogBigFuncs = map[string]func(x, base, mod *big.Int) (*big.Int, bool){
+  methodEnumeration:       modular.DLogBig,
+  methodBabyStepGiantStep: modular.DLogBabyStepGiantStepBig,
+  methodIndexCalculus:     dlogIndexCalculusBig,
+}
+
+func withoutTrace(f func(x, base, mod int32) (int32, bool)) dlogFunc {
+  return func(x, base, mod int32, _ modular.TraceFunc) (int32, bool) {
+    return f(x, base, mod)
+  }
+}
+
+func dlogIndexCalculusBig(x, base, mod *big.Int) (*big.Int, bool) {
+  if x.Sign() <= 0 || base.Sign() <= 0 || mod.Sign() <= 0 {
+    panic("x, base and modulus must be greater than 0")
+  }
+  if !mod.IsUint64() {
+    panic("modulus too large for index calculus")
+  }
+  if x.Cmp(mod) >= 0 {
+    return nil, false
+  }
+
+  dlog, exists := modular.DLogIndexCalculus64(x.Uint64(), new(big.Int).Mod(base, mod).Uint64(), mod.Uint64())
+  return new(big.Int).SetUint64(dlog), exists
+}
+
+func dlogIndexCalculus(x, base, mod int32, _ modular.TraceFunc) (int32, bool) {
+  if x <= 0 || base <= 0 || mod <= 0 {
+    panic("x, base and modulus must be greater than 0")
+  }
+
+  dlog, exists := modular.DLogIndexCalculus64(uint64(x), uint64(base), uint64(mod))
+  return int32(dlog), exists
+}
+
 func NewCommand() *cobra.Command {
-  var x, base, mod int32
+  var (
+    ints    []*big.Int
+    method  string
+    verbose bool
+  )
 
   cmd := &cobra.Command{
     Use:   "dlog [x] [base] [modulus]",
     Short: "Calculate the discrete logarithm of x to the given base mod modulus",
-    Long: `dlog calculates the discrete logarithm of x to the given base and modulus by enumeration for int32 numbers.
+    Long: `dlog calculates the discrete logarithm of x to the given base and modulus.
 The discrete logarithm of a number x to the base of b modulo m is defined as the smallest number y,
 so that b^y ≡ x mod m. dlog is the inverse operation to exp.
+If any of the arguments doesn't fit into int32, arbitrary-precision arithmetic is used, which is only supported by
+the enumeration, bsgs and index-calculus methods.
 
 Enumeration is a very simple approach to calculate dlog. It calculates b^i for i=0,1,...,m until b^i=x.
 While being simple, the algorithm can take up to order(b) steps in the worst case, so it is very impractical
-for bases with large order.
+for bases with large order. Enumeration is only supported for moduli up to ` + strconv.Itoa(modular.DLogMaxMod) + `.
+
+The baby-step giant-step algorithm (--method bsgs) trades memory for time: it writes the exponent as y = n*p - q with
+n = ⌈√m⌉ and compares precomputed "baby steps" x*b^q with "giant steps" b^(n*p). This way, it needs only O(√m) steps
+and works for the full int32 range.
+
+Pollard's rho algorithm (--method rho) also needs O(√n) steps but only constant memory. It performs a pseudo-random
+walk through the group generated by b until it runs into a cycle and solves the resulting collision equation.
+It requires the base to be coprime to the modulus and only supports arguments up to MaxInt32.
+
+The Pohlig-Hellman algorithm (--method pohlig-hellman) factors the order n of the base and solves dlog in each
+subgroup of prime power order separately. The results are combined using the chinese remainder theorem. Its runtime
+depends on the largest prime factor of n, so dlog is easy if n only has small prime factors. It only supports
+arguments up to MaxInt32. Use --verbose to print all subproblems.
+
+The index calculus algorithm (--method index-calculus) only works for prime moduli. It collects relations between
+powers of a generator and small primes (the factor base), solves the resulting linear system for the logarithms of
+the factor base and uses them to calculate the logarithm of x. In contrast to the other methods, it has a
+subexponential runtime.
 
 Calculating the discrete logarithm is thought to be hard, so currently there is no known algorithm for solving
 it efficiently. The security of some cryptographic algorithms (e.g. Diffie-Hellman, ElGamal and others) is based
@@ -30,32 +117,15 @@ on exactly this assumption, that DLog is hard.
 See https://en.wikipedia.org/wiki/Discrete_logarithm.`,
     Args: cobra.ExactArgs(3),
     PreRunE: func(cmd *cobra.Command, args []string) error {
-      xIn, err := strconv.Atoi(args[0])
-      if err != nil {
-        return fmt.Errorf("first argument is not an int: %w", err)
-      }
-      if xIn > math.MaxInt32 {
-        return fmt.Errorf("x is greater than MaxInt32 (%d): %d", math.MaxInt32, xIn)
+      if _, ok := dlogFuncs[method]; !ok {
+        return fmt.Errorf("unknown method %q, must be one of %v", method, methods)
       }
-      x = int32(xIn)
 
-      b, err := strconv.Atoi(args[1])
+      var err error
+      ints, err = options.ParseInts(args)
       if err != nil {
-        return fmt.Errorf("second argument is not an int: %w", err)
-      }
-      if b > math.MaxInt32 {
-        return fmt.Errorf("base is greater than MaxInt32 (%d): %d", math.MaxInt32, b)
+        return err
       }
-      base = int32(b)
-
-      m, err := strconv.Atoi(args[2])
-      if err != nil {
-        return fmt.Errorf("third argument is not an int: %w", err)
-      }
-      if m > math.MaxInt32 {
-        return fmt.Errorf("modulus is greater than MaxInt32 (%d): %d", math.MaxInt32, b)
-      }
-      mod = int32(m)
 
       cmd.SilenceErrors = true
       cmd.SilenceUsage = true
@@ -63,14 +133,21 @@ See https://en.wikipedia.org/wiki/Discrete_logarithm.`,
       return nil
     },
     RunE: func(cmd *cobra.Command, args []string) error {
-      return runDLog32(x, base, mod)
+      if options.FitInt32(ints...) {
+        return runDLog32(int32(ints[0].Int64()), int32(ints[1].Int64()), int32(ints[2].Int64()), method, verbose)
+      }
+      return runDLogBig(ints[0], ints[1], ints[2], method)
     },
   }
 
+  cmd.Flags().StringVarP(&method, "method", "m", methodEnumeration,
+    fmt.Sprintf("algorithm to use for calculating dlog, one of %v", methods))
+  cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "print intermediate steps (only supported by pohlig-hellman)")
+
   return cmd
 }
 
-func runDLog32(x, base, mod int32) (err error) {
+func runDLog32(x, base, mod int32, method string, verbose bool) (err error) {
   defer func() {
     if p := recover(); p != nil {
       if e, ok := p.(error); ok {
@@ -82,7 +159,14 @@ func runDLog32(x, base, mod int32) (err error) {
     }
   }()
 
-  dlog, exists := modular.DLog32(x, base, mod)
+  var trace modular.TraceFunc
+  if verbose {
+    trace = func(format string, a ...interface{}) {
+      fmt.Printf(format+"\n", a...)
+    }
+  }
+
+  dlog, exists := dlogFuncs[method](x, base, mod, trace)
   if !exists {
     return fmt.Errorf("dlog(%d) to the base %d mod %d does not exist\n", x, base, mod)
   }
@@ -91,3 +175,30 @@ func runDLog32(x, base, mod int32) (err error) {
 
   return nil
 }
+
+func runDLogBig(x, base, mod *big.Int, method string) (err error) {
+  defer func() {
+    if p := recover(); p != nil {
+      if e, ok := p.(error); ok {
+        err = e
+      }
+      if e, ok := p.(string); ok {
+        err = fmt.Errorf(e)
+      }
+    }
+  }()
+
+  dlogFunc, ok := dlogBigFuncs[method]
+  if !ok {
+    return fmt.Errorf("method %q does not support arguments greater than MaxInt32 (%d)", method, math.MaxInt32)
+  }
+
+  dlog, exists := dlogFunc(x, base, mod)
+  if !exists {
+    return fmt.Errorf("dlog(%s) to the base %s mod %s does not exist\n", x, base, mod)
+  }
+
+  fmt.Printf("dlog(%s) to the base %s mod %s = %s\n", x, base, mod, dlog)
+
+  return nil
+}
@@ -2,16 +2,18 @@ package euclid
 
 import (
   "fmt"
-  "strconv"
+  "math/big"
 
   "github.com/spf13/cobra"
 
   "github.com/timebertt/grypto/euclid"
+  "github.com/timebertt/grypto/grypto/options"
   "github.com/timebertt/grypto/internal/unicode"
+  "github.com/timebertt/grypto/modular"
 )
 
 func NewCommand() *cobra.Command {
-  var a, b int
+  var ints []*big.Int
 
   cmd := &cobra.Command{
     Use:     "euclid [a] [b]",
@@ -22,22 +24,22 @@ of two integers. It is a  based on the following two cases:
   gcd(a, 0) = 0
   gcd(a, b) = gcd(b, a mod b)
 
-In it not only calculates the gcd of two integers but additionally calculates two integers a and b, such that
+The extended euclidean algorithm not only calculates the gcd of two integers but additionally calculates two
+integers x and y, such that
   gcd(a, b) = x*a + y*b
-If gcd(a, b) = 1, y is b's multiplicative inverse in ` + unicode.ZSubscriptSmallA + ` (y * b ` + unicode.IdenticalTo + ` 1 mod a).
+If gcd(a, b) = 1, x is a's multiplicative inverse modulo b (x * a ` + unicode.IdenticalTo + ` 1 mod b) and y is b's
+multiplicative inverse in ` + unicode.ZSubscriptSmallA + ` (y * b ` + unicode.IdenticalTo + ` 1 mod a).
+Use the inverse command for calculating modular inverses.
+
+If any of the arguments doesn't fit into int, arbitrary-precision arithmetic is used.
 
 See: https://en.wikipedia.org/wiki/Euclidean_algorithm, https://en.wikipedia.org/wiki/Extended_Euclidean_algorithm`,
     Args: cobra.ExactArgs(2),
     PreRunE: func(cmd *cobra.Command, args []string) error {
       var err error
-      a, err = strconv.Atoi(args[0])
-      if err != nil {
-        return fmt.Errorf("first argument is not an int: %w", err)
-      }
-
-      b, err = strconv.Atoi(args[1])
+      ints, err = options.ParseInts(args)
       if err != nil {
-        return fmt.Errorf("second argument is not an int: %w", err)
+        return err
       }
 
       cmd.SilenceErrors = true
@@ -46,7 +48,10 @@ See: https://en.wikipedia.org/wiki/Euclidean_algorithm, https://en.wikipedia.org
       return nil
     },
     RunE: func(cmd *cobra.Command, args []string) error {
-      return runEuclid(a, b)
+      if options.FitInt(ints...) {
+        return runEuclid(int(ints[0].Int64()), int(ints[1].Int64()))
+      }
+      return runEuclidBig(ints[0], ints[1])
     },
   }
 
@@ -69,10 +74,10 @@ func runEuclid(a, b int) (err error) {
 
   fmt.Printf("gcd(%d,%d) = %d = %s*%d + %s*%d\n", a, b, gcd, parenthesis(x), a, parenthesis(y), b)
 
-  if gcd == 1 {
-    inv := y
-    if inv < 0 {
-      inv += a // normalize inverse, y might be negative
+  if gcd == 1 && a > 0 {
+    inv, err := modular.Inverse64(int64(b), int64(a))
+    if err != nil {
+      return err
     }
 
     fmt.Printf("=> %d%s %s %d mod %d\n", b, unicode.SuperscriptMinusOne, unicode.IdenticalTo, inv, a)
@@ -81,9 +86,44 @@ func runEuclid(a, b int) (err error) {
   return nil
 }
 
+func runEuclidBig(a, b *big.Int) (err error) {
+  defer func() {
+    if p := recover(); p != nil {
+      if e, ok := p.(error); ok {
+        err = e
+      }
+      if e, ok := p.(string); ok {
+        err = fmt.Errorf(e)
+      }
+    }
+  }()
+
+  gcd, x, y := euclid.GreatestCommonDivisorExtendedBig(a, b)
+
+  fmt.Printf("gcd(%s,%s) = %s = %s*%s + %s*%s\n", a, b, gcd, parenthesisBig(x), a, parenthesisBig(y), b)
+
+  if gcd.Cmp(big.NewInt(1)) == 0 && a.Sign() > 0 {
+    inv, err := modular.InverseBig(b, a)
+    if err != nil {
+      return err
+    }
+
+    fmt.Printf("=> %s%s %s %s mod %s\n", b, unicode.SuperscriptMinusOne, unicode.IdenticalTo, inv, a)
+  }
+
+  return nil
+}
+
 func parenthesis(i int) string {
   if i < 0 {
     return fmt.Sprintf("(%d)", i)
   }
   return fmt.Sprintf("%d", i)
 }
+
+func parenthesisBig(i *big.Int) string {
+  if i.Sign() < 0 {
+    return fmt.Sprintf("(%s)", i)
+  }
+  return i.String()
+}
@@ -2,23 +2,24 @@ package exp
 
 import (
   "fmt"
-  "math"
-  "strconv"
+  "math/big"
 
   "github.com/spf13/cobra"
 
+  "github.com/timebertt/grypto/grypto/options"
   "github.com/timebertt/grypto/modular"
 )
 
 func NewCommand() *cobra.Command {
-  var base, exp, mod int32
+  var ints []*big.Int
 
   cmd := &cobra.Command{
     Use:     "exp [base] [exponent] [modulus]",
     Aliases: []string{"mod-exp", "square-and-multiply"},
     Short:   "Use the square-and-multiply method for calculating the modular exponentiation",
-    Long: `The exp command implements modular exponentiation using the square-and-multiply method for int32 numbers.
-It prints a value x so that x = base ^ exp mod m.
+    Long: `The exp command implements modular exponentiation using the square-and-multiply method.
+It prints a value x so that x = base ^ exp mod m. If any of the arguments doesn't fit into int32, arbitrary-precision
+arithmetic is used.
 
 Modular exponentiation is heavily used e.g. for primality tests and public-key cryptography (like RSA).
 Even for reasonably small integers, calculating the modular exponentiation directly is on the one hand
@@ -31,32 +32,11 @@ has a 1 in its binary notation.
 See https://en.wikipedia.org/wiki/Exponentiation_by_squaring.`,
     Args: cobra.ExactArgs(3),
     PreRunE: func(cmd *cobra.Command, args []string) error {
-      b, err := strconv.Atoi(args[0])
+      var err error
+      ints, err = options.ParseInts(args)
       if err != nil {
-        return fmt.Errorf("first argument is not an int: %w", err)
+        return err
       }
-      if b > math.MaxInt32 {
-        return fmt.Errorf("base is greater than MaxInt32 (%d): %d", math.MaxInt32, b)
-      }
-      base = int32(b)
-
-      e, err := strconv.Atoi(args[1])
-      if err != nil {
-        return fmt.Errorf("second argument is not an int: %w", err)
-      }
-      if e > math.MaxInt32 {
-        return fmt.Errorf("exponent is greater than MaxInt32 (%d): %d", math.MaxInt32, b)
-      }
-      exp = int32(e)
-
-      m, err := strconv.Atoi(args[2])
-      if err != nil {
-        return fmt.Errorf("third argument is not an int: %w", err)
-      }
-      if m > math.MaxInt32 {
-        return fmt.Errorf("modulus is greater than MaxInt32 (%d): %d", math.MaxInt32, b)
-      }
-      mod = int32(m)
 
       cmd.SilenceErrors = true
       cmd.SilenceUsage = true
@@ -64,7 +44,10 @@ See https://en.wikipedia.org/wiki/Exponentiation_by_squaring.`,
       return nil
     },
     RunE: func(cmd *cobra.Command, args []string) error {
-      return runPow32(base, exp, mod)
+      if options.FitInt32(ints...) {
+        return runPow32(int32(ints[0].Int64()), int32(ints[1].Int64()), int32(ints[2].Int64()))
+      }
+      return runPowBig(ints[0], ints[1], ints[2])
     },
   }
 
@@ -89,9 +72,34 @@ func runPow32(base, exp, mod int32) (err error) {
   return nil
 }
 
+func runPowBig(base, exp, mod *big.Int) (err error) {
+  defer func() {
+    if p := recover(); p != nil {
+      if e, ok := p.(error); ok {
+        err = e
+      }
+      if e, ok := p.(string); ok {
+        err = fmt.Errorf(e)
+      }
+    }
+  }()
+
+  pow := modular.PowBig(base, exp, mod)
+
+  fmt.Printf("%s ^ %s mod %s = %s\n", parenthesisBig(base), parenthesisBig(exp), parenthesisBig(mod), pow)
+  return nil
+}
+
 func parenthesis(i int32) string {
   if i < 0 {
     return fmt.Sprintf("(%d)", i)
   }
   return fmt.Sprintf("%d", i)
 }
+
+func parenthesisBig(i *big.Int) string {
+  if i.Sign() < 0 {
+    return fmt.Sprintf("(%s)", i)
+  }
+  return i.String()
+}
@@ -19,12 +19,17 @@ package cmd
 import (
   "github.com/spf13/cobra"
 
+  "github.com/timebertt/grypto/grypto/cmd/affine"
   "github.com/timebertt/grypto/grypto/cmd/caesar"
+  "github.com/timebertt/grypto/grypto/cmd/crt"
   "github.com/timebertt/grypto/grypto/cmd/dlog"
   "github.com/timebertt/grypto/grypto/cmd/euclid"
   "github.com/timebertt/grypto/grypto/cmd/exp"
+  "github.com/timebertt/grypto/grypto/cmd/inverse"
   "github.com/timebertt/grypto/grypto/cmd/order"
+  "github.com/timebertt/grypto/grypto/cmd/prime"
   "github.com/timebertt/grypto/grypto/cmd/subgroup"
+  "github.com/timebertt/grypto/grypto/cmd/vigenere"
 )
 
 func NewGryptoCommand() *cobra.Command {
@@ -49,12 +54,17 @@ for writing secure Go applications.`,
   }
 
   cmd.AddCommand(
+    affine.NewCommand(),
     caesar.NewCommand(),
+    crt.NewCommand(),
     dlog.NewCommand(),
     exp.NewCommand(),
     euclid.NewCommand(),
+    inverse.NewCommand(),
     order.NewCommand(),
+    prime.NewCommand(),
     subgroup.NewCommand(),
+    vigenere.NewCommand(),
   )
 
   return cmd
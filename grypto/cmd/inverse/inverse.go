@@ -0,0 +1,111 @@
+package inverse
+
+import (
+  "fmt"
+  "math/big"
+
+  "github.com/spf13/cobra"
+
+  "github.com/timebertt/grypto/grypto/options"
+  "github.com/timebertt/grypto/internal/unicode"
+  "github.com/timebertt/grypto/modular"
+)
+
+func NewCommand() *cobra.Command {
+  var ints []*big.Int
+
+  cmd := &cobra.Command{
+    Use:     "inverse [a] [modulus]",
+    Aliases: []string{"inv"},
+    Short:   "Calculate the multiplicative inverse of a modulo modulus",
+    Long: `inverse calculates the multiplicative inverse of a modulo m, i.e. the number a` +
+      unicode.SuperscriptMinusOne + ` in the range [0, m),
+so that a` + unicode.MiddleDot + `a` + unicode.SuperscriptMinusOne + ` ` + unicode.IdenticalTo + ` 1 mod m.
+a is invertible modulo m if and only if gcd(a, m) = 1. In this case, the extended euclidean algorithm yields
+two integers x and y, so that x*a + y*m = 1, i.e. x*a ` + unicode.IdenticalTo + ` 1 mod m.
+If any of the arguments doesn't fit into int, arbitrary-precision arithmetic is used.
+
+See https://en.wikipedia.org/wiki/Modular_multiplicative_inverse.`,
+    Args: cobra.ExactArgs(2),
+    PreRunE: func(cmd *cobra.Command, args []string) error {
+      var err error
+      ints, err = options.ParseInts(args)
+      if err != nil {
+        return err
+      }
+
+      cmd.SilenceErrors = true
+      cmd.SilenceUsage = true
+
+      return nil
+    },
+    RunE: func(cmd *cobra.Command, args []string) error {
+      if options.FitInt(ints...) {
+        return runInverse64(ints[0].Int64(), ints[1].Int64())
+      }
+      return runInverseBig(ints[0], ints[1])
+    },
+  }
+
+  return cmd
+}
+
+func runInverse64(a, m int64) (err error) {
+  defer func() {
+    if p := recover(); p != nil {
+      if e, ok := p.(error); ok {
+        err = e
+      }
+      if e, ok := p.(string); ok {
+        err = fmt.Errorf(e)
+      }
+    }
+  }()
+
+  inv, err := modular.Inverse64(a, m)
+  if err != nil {
+    return err
+  }
+
+  fmt.Printf("%s%s %s %d mod %d\n", parenthesis(a), unicode.SuperscriptMinusOne, unicode.IdenticalTo, inv, m)
+  fmt.Printf("%s%s%d %s 1 mod %d\n", parenthesis(a), unicode.MiddleDot, inv, unicode.IdenticalTo, m)
+
+  return nil
+}
+
+func runInverseBig(a, m *big.Int) (err error) {
+  defer func() {
+    if p := recover(); p != nil {
+      if e, ok := p.(error); ok {
+        err = e
+      }
+      if e, ok := p.(string); ok {
+        err = fmt.Errorf(e)
+      }
+    }
+  }()
+
+  inv, err := modular.InverseBig(a, m)
+  if err != nil {
+    return err
+  }
+
+  fmt.Printf("%s%s %s %s mod %s\n", parenthesisBig(a), unicode.SuperscriptMinusOne, unicode.IdenticalTo, inv, m)
+  fmt.Printf("%s%s%s %s 1 mod %s\n", parenthesisBig(a), unicode.MiddleDot, inv, unicode.IdenticalTo, m)
+
+  return nil
+}
+
+func parenthesis(i int64) string {
+  if i < 0 {
+    return fmt.Sprintf("(%d)", i)
+  }
+  return fmt.Sprintf("%d", i)
+}
+
+func parenthesisBig(i *big.Int) string {
+  if i.Sign() < 0 {
+    return fmt.Sprintf("(%s)", i)
+  }
+  return i.String()
+}
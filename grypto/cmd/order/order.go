@@ -2,16 +2,17 @@ package order
 
 import (
   "fmt"
-  "math"
+  "math/big"
   "strconv"
 
   "github.com/spf13/cobra"
 
+  "github.com/timebertt/grypto/grypto/options"
   "github.com/timebertt/grypto/modular"
 )
 
 func NewCommand() *cobra.Command {
-  var base, mod int32
+  var ints []*big.Int
 
   cmd := &cobra.Command{
     Use:   "order [base] [modulus]",
@@ -22,26 +23,19 @@ If not, the order of g is infinite.
 Also, the order of g in ℤₐ is equal to the magnitude of the multiplicative subgroup generated by g modulo a:
 order(g) = order(⟨g+aℤ⟩) = |⟨g+aℤ⟩|
 
+If any of the arguments doesn't fit into int32, the order is calculated by factorizing φ(a) instead of enumerating
+all powers of g, which works for arguments up to 2^64-1. As φ(a) is factorized using trial division, this is only
+fast, if a and φ(a) don't have more than one large prime factor. For larger arguments, arbitrary-precision
+arithmetic is used, which enumerates all powers of g and is only practicable for elements with small order.
+
 See https://en.wikipedia.org/wiki/Order_(group_theory)`,
     Args: cobra.ExactArgs(2),
     PreRunE: func(cmd *cobra.Command, args []string) error {
-      b, err := strconv.Atoi(args[0])
-      if err != nil {
-        return fmt.Errorf("first argument is not an int: %w", err)
-      }
-      if b > math.MaxInt32 {
-        return fmt.Errorf("base is greater than MaxInt32 (%d): %d", math.MaxInt32, b)
-      }
-      base = int32(b)
-
-      m, err := strconv.Atoi(args[1])
+      var err error
+      ints, err = options.ParseInts(args)
       if err != nil {
-        return fmt.Errorf("second argument is not an int: %w", err)
-      }
-      if m > math.MaxInt32 {
-        return fmt.Errorf("modulus is greater than MaxInt32 (%d): %d", math.MaxInt32, b)
+        return err
       }
-      mod = int32(m)
 
       cmd.SilenceErrors = true
       cmd.SilenceUsage = true
@@ -49,7 +43,13 @@ See https://en.wikipedia.org/wiki/Order_(group_theory)`,
       return nil
     },
     RunE: func(cmd *cobra.Command, args []string) error {
-      return runOrder(base, mod)
+      if options.FitInt32(ints...) {
+        return runOrder(int32(ints[0].Int64()), int32(ints[1].Int64()))
+      }
+      if ints[0].IsUint64() && ints[1].IsUint64() {
+        return runOrder64(ints[0].Uint64(), ints[1].Uint64())
+      }
+      return runOrderBig(ints[0], ints[1])
     },
   }
 
@@ -77,3 +77,47 @@ func runOrder(base, mod int32) (err error) {
   fmt.Printf("order(%d) = %s mod %d\n", base, o, mod)
   return nil
 }
+
+func runOrder64(base, mod uint64) (err error) {
+  defer func() {
+    if p := recover(); p != nil {
+      if e, ok := p.(error); ok {
+        err = e
+      }
+      if e, ok := p.(string); ok {
+        err = fmt.Errorf(e)
+      }
+    }
+  }()
+
+  order, inf := modular.OrderOf64(base, mod)
+  o := strconv.FormatUint(order, 10)
+  if inf {
+    o = "Inf"
+  }
+
+  fmt.Printf("order(%d) = %s mod %d\n", base, o, mod)
+  return nil
+}
+
+func runOrderBig(base, mod *big.Int) (err error) {
+  defer func() {
+    if p := recover(); p != nil {
+      if e, ok := p.(error); ok {
+        err = e
+      }
+      if e, ok := p.(string); ok {
+        err = fmt.Errorf(e)
+      }
+    }
+  }()
+
+  order, inf := modular.OrderOfBig(base, mod)
+  o := "Inf"
+  if !inf {
+    o = order.String()
+  }
+
+  fmt.Printf("order(%s) = %s mod %s\n", base, o, mod)
+  return nil
+}
@@ -0,0 +1,19 @@
+package prime
+
+import (
+  "github.com/spf13/cobra"
+)
+
+func NewCommand() *cobra.Command {
+  cmd := &cobra.Command{
+    Use:   "prime",
+    Short: "Work with prime numbers",
+    Long: `prime groups commands for working with prime numbers.
+Prime numbers are essential for public-key cryptography (e.g. RSA, Diffie-Hellman), which needs large random primes.
+Such primes are typically found by choosing random numbers and testing them for primality.`,
+  }
+
+  cmd.AddCommand(newTestCommand())
+
+  return cmd
+}
@@ -0,0 +1,172 @@
+package prime
+
+import (
+  "crypto/rand"
+  "fmt"
+  "math"
+  "math/big"
+
+  "github.com/spf13/cobra"
+
+  "github.com/timebertt/grypto/grypto/options"
+  "github.com/timebertt/grypto/prime"
+)
+
+const (
+  methodTrialDivision   = "trial"
+  methodFermat          = "fermat"
+  methodSolovayStrassen = "solovay-strassen"
+  methodMillerRabin     = "miller-rabin"
+  methodBailliePSW      = "baillie-psw"
+)
+
+var methods = []string{methodTrialDivision, methodFermat, methodSolovayStrassen, methodMillerRabin, methodBailliePSW}
+
+func newTestCommand() *cobra.Command {
+  var (
+    n       *big.Int
+    method  string
+    rounds  int
+    verbose bool
+  )
+
+  cmd := &cobra.Command{
+    Use:   "test [n]",
+    Short: "Test n for primality",
+    Long: `test checks whether n is prime using one of the following primality tests.
+
+Trial division (--method trial) tests for every i = 2, 3, ... <= √n if n can be divided by i. It is deterministic
+but very inefficient for large n.
+
+Fermat's primality test (--method fermat) repeatedly chooses a random integer a and checks if a^(n-1) ≡ 1 mod n,
+which must be true if n is prime (Fermat's little theorem). If n is composite, a is called a Fermat witness, if it
+contradicts the theorem, or a Fermat liar, if it doesn't. For most composite numbers, at least half of
+all a are witnesses, so each round halves the error probability. However, for Carmichael numbers (e.g. 561) all a
+coprime to n are liars.
+
+The Solovay-Strassen primality test (--method solovay-strassen) checks if a^((n-1)/2) ≡ (a/n) mod n, where (a/n) is
+the Jacobi symbol, which must be true if n is prime (Euler's criterion). At most half of all a are Euler liars for
+any composite n (including Carmichael numbers), so the error probability after k rounds is at most 2^-k.
+
+The Miller-Rabin primality test (--method miller-rabin) writes n-1 = 2^s * d and checks if a^d ≡ 1 mod n or
+a^(2^r * d) ≡ -1 mod n for some r < s. At most a quarter of all a are strong liars for any composite n, so the error
+probability after k rounds is at most 4^-k.
+
+The Baillie-PSW primality test (--method baillie-psw) combines a strong probable prime test to base 2 (a single
+Miller-Rabin round) with a strong Lucas probable prime test. There is no known composite number passing both tests,
+and for n < 2^64 it has been verified that there is none. It supports arbitrarily large n and ignores --rounds.
+
+If a probabilistic test finds a witness, n is definitely composite. Otherwise, n is probably prime.
+Use --verbose to print the witness or liar found in each round. If n doesn't fit into int32, only miller-rabin
+(for n up to 2^64-1) and baillie-psw are supported.
+
+See https://en.wikipedia.org/wiki/Primality_test.`,
+    Args: cobra.ExactArgs(1),
+    PreRunE: func(cmd *cobra.Command, args []string) error {
+      if !isKnownMethod(method) {
+        return fmt.Errorf("unknown method %q, must be one of %v", method, methods)
+      }
+      if rounds <= 0 {
+        return fmt.Errorf("rounds must be greater than 0")
+      }
+
+      ints, err := options.ParseInts(args)
+      if err != nil {
+        return err
+      }
+      n = ints[0]
+
+      cmd.SilenceErrors = true
+      cmd.SilenceUsage = true
+
+      return nil
+    },
+    RunE: func(cmd *cobra.Command, args []string) error {
+      return runTest(n, method, rounds, verbose)
+    },
+  }
+
+  cmd.Flags().StringVarP(&method, "method", "m", methodMillerRabin,
+    fmt.Sprintf("primality test to use, one of %v", methods))
+  cmd.Flags().IntVarP(&rounds, "rounds", "r", 12, "number of rounds for probabilistic tests")
+  cmd.Flags().BoolVarP(&verbose, "verbose", "v", false,
+    "print the witness or liar found in each round (only supported by probabilistic tests)")
+
+  return cmd
+}
+
+func isKnownMethod(method string) bool {
+  for _, m := range methods {
+    if m == method {
+      return true
+    }
+  }
+  return false
+}
+
+func runTest(n *big.Int, method string, rounds int, verbose bool) (err error) {
+  defer func() {
+    if p := recover(); p != nil {
+      if e, ok := p.(error); ok {
+        err = e
+      }
+      if e, ok := p.(string); ok {
+        err = fmt.Errorf(e)
+      }
+    }
+  }()
+
+  // use cryptographically secure randomness for choosing the random integers
+  opts := prime.Options{Rounds: rounds, Rand: rand.Reader}
+  if verbose {
+    opts.Trace = func(format string, a ...interface{}) {
+      fmt.Printf(format+"\n", a...)
+    }
+  }
+
+  var isPrime bool
+  switch {
+  case method == methodBailliePSW:
+    isPrime = prime.IsPrimeBailliePSWBig(n)
+  case options.FitInt32(n):
+    p := int32(n.Int64())
+    switch method {
+    case methodTrialDivision:
+      isPrime = prime.IsPrimeTrialDivision32(p)
+    case methodFermat:
+      isPrime = prime.IsPrimeFermatWithOptions32(p, opts)
+    case methodSolovayStrassen:
+      isPrime = prime.IsPrimeSolovayStrassenWithOptions32(p, opts)
+    case methodMillerRabin:
+      isPrime = prime.IsPrimeMillerRabinWithOptions32(p, opts)
+    }
+  case n.Sign() < 0:
+    // negative numbers are not prime
+  case method == methodMillerRabin && n.IsUint64():
+    isPrime = prime.IsPrimeMillerRabinWithOptions64(n.Uint64(), opts)
+  case method == methodMillerRabin:
+    return fmt.Errorf("method %q does not support n greater than MaxUint64 (%d)", method, uint64(math.MaxUint64))
+  default:
+    return fmt.Errorf("method %q does not support n greater than MaxInt32 (%d)", method, math.MaxInt32)
+  }
+
+  switch {
+  case !isPrime:
+    fmt.Printf("%s is not prime\n", n)
+  case method == methodTrialDivision:
+    fmt.Printf("%s is prime\n", n)
+  case method == methodFermat:
+    fmt.Printf("%s is probably prime (error probability <= 2^-%d = %g, unless %s is a Carmichael number)\n",
+      n, rounds, math.Pow(2, -float64(rounds)), n)
+  case method == methodSolovayStrassen:
+    fmt.Printf("%s is probably prime (error probability <= 2^-%d = %g)\n", n, rounds, math.Pow(2, -float64(rounds)))
+  case method == methodMillerRabin:
+    fmt.Printf("%s is probably prime (error probability <= 4^-%d = %g)\n", n, rounds, math.Pow(4, -float64(rounds)))
+  case method == methodBailliePSW && n.IsUint64():
+    fmt.Printf("%s is prime\n", n)
+  case method == methodBailliePSW:
+    fmt.Printf("%s is probably prime (no counterexample known)\n", n)
+  }
+
+  return nil
+}
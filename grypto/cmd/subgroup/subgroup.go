@@ -2,16 +2,16 @@ package subgroup
 
 import (
   "fmt"
-  "math"
-  "strconv"
+  "math/big"
 
   "github.com/spf13/cobra"
 
+  "github.com/timebertt/grypto/grypto/options"
   "github.com/timebertt/grypto/modular"
 )
 
 func NewCommand() *cobra.Command {
-  var base, mod int32
+  var ints []*big.Int
 
   cmd := &cobra.Command{
     Use:   "subgroup [base] [modulus]",
@@ -19,27 +19,16 @@ func NewCommand() *cobra.Command {
     Long: `SubgroupOf calculates the multiplicative subgroup generated by base modulo mod.
 The subgroup for an element g in ℤₐ (denoted as ⟨g+aℤ⟩) contains all elements g^x mod a with x ∈ ℤ.
 The calculation stops once it encounters 0 as any g^x mod a.
+If any of the arguments doesn't fit into int32, arbitrary-precision arithmetic is used.
 
 See https://en.wikipedia.org/wiki/Subgroup`,
     Args: cobra.ExactArgs(2),
     PreRunE: func(cmd *cobra.Command, args []string) error {
-      b, err := strconv.Atoi(args[0])
+      var err error
+      ints, err = options.ParseInts(args)
       if err != nil {
-        return fmt.Errorf("first argument is not an int: %w", err)
+        return err
       }
-      if b > math.MaxInt32 {
-        return fmt.Errorf("base is greater than MaxInt32 (%d): %d", math.MaxInt32, b)
-      }
-      base = int32(b)
-
-      m, err := strconv.Atoi(args[1])
-      if err != nil {
-        return fmt.Errorf("second argument is not an int: %w", err)
-      }
-      if m > math.MaxInt32 {
-        return fmt.Errorf("modulus is greater than MaxInt32 (%d): %d", math.MaxInt32, b)
-      }
-      mod = int32(m)
 
       cmd.SilenceErrors = true
       cmd.SilenceUsage = true
@@ -47,7 +36,10 @@ See https://en.wikipedia.org/wiki/Subgroup`,
       return nil
     },
     RunE: func(cmd *cobra.Command, args []string) error {
-      return runSubgroup(base, mod)
+      if options.FitInt32(ints...) {
+        return runSubgroup(int32(ints[0].Int64()), int32(ints[1].Int64()))
+      }
+      return runSubgroupBig(ints[0], ints[1])
     },
   }
 
@@ -72,3 +64,22 @@ func runSubgroup(base, mod int32) (err error) {
 
   return nil
 }
+
+func runSubgroupBig(base, mod *big.Int) (err error) {
+  defer func() {
+    if p := recover(); p != nil {
+      if e, ok := p.(error); ok {
+        err = e
+      }
+      if e, ok := p.(string); ok {
+        err = fmt.Errorf(e)
+      }
+    }
+  }()
+
+  for i, e := range modular.SubgroupOfBig(base, mod) {
+    fmt.Printf("%s ^ %d mod %s = %s\n", base, i, mod, e)
+  }
+
+  return nil
+}
@@ -0,0 +1,96 @@
+package vigenere
+
+import (
+  "bytes"
+  "crypto/cipher"
+  "fmt"
+  "io"
+  "os"
+  "strings"
+
+  "github.com/spf13/cobra"
+
+  "github.com/timebertt/grypto/grypto/options"
+  "github.com/timebertt/grypto/vigenere"
+)
+
+const (
+  CipherName = "Vigenère Cipher"
+)
+
+func NewCommand() *cobra.Command {
+  var (
+    input = &options.Input{}
+    key   = &options.Key{}
+
+    parsedKey string
+  )
+
+  cmd := &cobra.Command{
+    Use:   "vigenere",
+    Short: "Use the " + CipherName + " for encryption and decryption",
+    Long: `The ` + CipherName + ` is a polyalphabetic substitution cipher, that is keyed by a word.
+Each latin character is encrypted with the Caesar Cipher using the next letter of the key as the Caesar key
+(A=0, B=1, ..., Z=25). Once all letters of the key are used, it starts again with the first letter.
+The characters' case is kept and non-latin characters are not replaced.
+See: https://en.wikipedia.org/wiki/Vigen%C3%A8re_cipher`,
+    PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+      if err := input.Complete(cmd, args); err != nil {
+        return err
+      }
+      if err := key.Complete(cmd, args); err != nil {
+        return err
+      }
+
+      keyInput := &bytes.Buffer{}
+      if _, err := io.Copy(keyInput, key.In); err != nil {
+        return fmt.Errorf("error reading input: %w", err)
+      }
+      if keyInput.Len() == 0 {
+        return fmt.Errorf("given key is empty")
+      }
+      parsedKey = strings.TrimSuffix(keyInput.String(), "\n")
+
+      cmd.SilenceErrors = true
+      cmd.SilenceUsage = true
+
+      return nil
+    },
+    PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+      if err := input.PostRun(cmd, args); err != nil {
+        return err
+      }
+      if err := key.PostRun(cmd, args); err != nil {
+        return err
+      }
+      return nil
+    },
+  }
+
+  options.AddEncryptDecryptSubcommands(cmd, CipherName, func(cmd *cobra.Command, direction options.Direction, args []string) error {
+    return runVigenere(direction, parsedKey, input.In)
+  })
+
+  input.AddFlags(cmd.PersistentFlags())
+  key.AddFlags(cmd.PersistentFlags())
+
+  return cmd
+}
+
+func runVigenere(direction options.Direction, key string, input io.Reader) error {
+  var (
+    stream cipher.Stream
+    err    error
+  )
+  if direction == options.Decrypt {
+    stream, err = vigenere.NewDecrypter(key)
+  } else {
+    stream, err = vigenere.NewEncrypter(key)
+  }
+  if err != nil {
+    return err
+  }
+
+  _, err = io.Copy(os.Stdout, cipher.StreamReader{S: stream, R: input})
+  return err
+}
@@ -0,0 +1,48 @@
+package options
+
+import (
+  "fmt"
+  "math"
+  "math/big"
+)
+
+var ordinals = []string{"first", "second", "third", "fourth", "fifth"}
+
+// ParseInts parses the given command line arguments as arbitrary-precision integers (base 10).
+// This allows commands to switch to the math/big variants of the algorithms, if the arguments are too large for
+// the fixed-size variants (see FitInt32).
+func ParseInts(args []string) ([]*big.Int, error) {
+  ints := make([]*big.Int, len(args))
+  for i, arg := range args {
+    v, ok := new(big.Int).SetString(arg, 10)
+    if !ok {
+      name := fmt.Sprintf("argument %d", i+1)
+      if i < len(ordinals) {
+        name = ordinals[i] + " argument"
+      }
+      return nil, fmt.Errorf("%s is not an int: %q", name, arg)
+    }
+    ints[i] = v
+  }
+  return ints, nil
+}
+
+// FitInt32 returns true if all given integers can be represented as int32.
+func FitInt32(ints ...*big.Int) bool {
+  for _, v := range ints {
+    if !v.IsInt64() || v.Int64() > math.MaxInt32 || v.Int64() < math.MinInt32 {
+      return false
+    }
+  }
+  return true
+}
+
+// FitInt returns true if all given integers can be represented as int.
+func FitInt(ints ...*big.Int) bool {
+  for _, v := range ints {
+    if !v.IsInt64() || int64(int(v.Int64())) != v.Int64() {
+      return false
+    }
+  }
+  return true
+}
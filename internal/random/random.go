@@ -0,0 +1,54 @@
+// Package random provides helpers for drawing uniformly distributed random numbers from an io.Reader.
+// Algorithms needing randomness (e.g. probabilistic primality tests or key generation) should accept an io.Reader
+// as their source of randomness. This allows using crypto/rand.Reader for cryptographically secure randomness as
+// well as a *math/rand.Rand with a fixed seed for reproducible results (e.g. in tests).
+package random
+
+import (
+  "encoding/binary"
+  "io"
+  "math"
+  "math/rand"
+)
+
+// Uint64 reads a random uint64 from r. If r is nil, the global math/rand source is used.
+// It panics if reading from r fails.
+func Uint64(r io.Reader) uint64 {
+  if r == nil {
+    return rand.Uint64()
+  }
+
+  var b [8]byte
+  if _, err := io.ReadFull(r, b[:]); err != nil {
+    panic("grypto/random: failed to read random bytes: " + err.Error())
+  }
+  return binary.BigEndian.Uint64(b[:])
+}
+
+// Uint64n returns a uniformly distributed random number in the range [0, n) read from r. If r is nil, the global
+// math/rand source is used. It panics if n is 0 or if reading from r fails.
+func Uint64n(r io.Reader, n uint64) uint64 {
+  if n == 0 {
+    panic("grypto/random: invalid argument to Uint64n")
+  }
+
+  // reject values from the last incomplete interval [max - max%n, max], which would bias the result towards small
+  // numbers (modulo bias)
+  limit := math.MaxUint64 - math.MaxUint64%n
+  for {
+    if v := Uint64(r); v < limit {
+      return v % n
+    }
+  }
+}
+
+// Int31n is like Uint64n but for int32 numbers. If r is nil, rand.Int31n is used. It panics if n <= 0.
+func Int31n(r io.Reader, n int32) int32 {
+  if n <= 0 {
+    panic("grypto/random: invalid argument to Int31n")
+  }
+  if r == nil {
+    return rand.Int31n(n)
+  }
+  return int32(Uint64n(r, uint64(n)))
+}
@@ -0,0 +1,80 @@
+package random_test
+
+import (
+  "bytes"
+  "encoding/binary"
+  "math"
+  "math/rand"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/internal/random"
+)
+
+// uint64s returns a reader returning the given uint64 values (big endian).
+func uint64s(values ...uint64) *bytes.Reader {
+  b := make([]byte, 8*len(values))
+  for i, v := range values {
+    binary.BigEndian.PutUint64(b[8*i:], v)
+  }
+  return bytes.NewReader(b)
+}
+
+var _ = Describe("Uint64n", func() {
+  It("should panic on invalid inputs", func() {
+    Expect(func() { random.Uint64n(nil, 0) }).To(Panic())
+    // not enough random bytes
+    Expect(func() { random.Uint64n(bytes.NewReader([]byte{1, 2, 3}), 5) }).To(Panic())
+  })
+
+  It("should return numbers in range", func() {
+    for _, r := range []*rand.Rand{nil, rand.New(rand.NewSource(1))} {
+      for _, n := range []uint64{1, 2, 3, 1000, math.MaxUint64} {
+        for i := 0; i < 100; i++ {
+          var v uint64
+          if r == nil {
+            v = random.Uint64n(nil, n)
+          } else {
+            v = random.Uint64n(r, n)
+          }
+          Expect(v).To(BeNumerically("<", n))
+        }
+      }
+    }
+  })
+
+  It("should reduce the read numbers", func() {
+    Expect(random.Uint64n(uint64s(7, 0, 1<<63), 5)).To(Equal(uint64(2)))
+    Expect(random.Uint64n(uint64s(math.MaxUint64-1), math.MaxUint64)).To(Equal(uint64(math.MaxUint64 - 1)))
+  })
+
+  It("should reject numbers causing modulo bias", func() {
+    // MaxUint64 % 3 = 0, so MaxUint64 is the only number in the last incomplete interval
+    Expect(random.Uint64n(uint64s(math.MaxUint64, 4), 3)).To(Equal(uint64(1)))
+    Expect(random.Uint64n(uint64s(math.MaxUint64, math.MaxUint64-1, 5), 1<<63+1)).To(Equal(uint64(5)))
+  })
+
+  It("should replay the same sequence for the same seed", func() {
+    r1, r2 := rand.New(rand.NewSource(42)), rand.New(rand.NewSource(42))
+    for i := 0; i < 100; i++ {
+      Expect(random.Uint64n(r1, 1000)).To(Equal(random.Uint64n(r2, 1000)))
+    }
+  })
+})
+
+var _ = Describe("Int31n", func() {
+  It("should panic on invalid inputs", func() {
+    Expect(func() { random.Int31n(nil, 0) }).To(Panic())
+    Expect(func() { random.Int31n(uint64s(1), -1) }).To(Panic())
+  })
+
+  It("should return numbers in range", func() {
+    for _, n := range []int32{1, 2, 3, 1000, math.MaxInt32} {
+      for i := 0; i < 100; i++ {
+        Expect(random.Int31n(nil, n)).To(And(BeNumerically(">=", 0), BeNumerically("<", n)))
+      }
+    }
+    Expect(random.Int31n(uint64s(1000), 7)).To(Equal(int32(6)))
+  })
+})
@@ -7,4 +7,5 @@ const (
   AngleBracketLeft    = "\u27E8"       // "⟨"
   AngleBracketRight   = "\u27E9"       // "⟩"
   Element             = "\u2208"       // "∈"
+  MiddleDot           = "\u00B7"       // "·"
 )
@@ -0,0 +1,92 @@
+package modular
+
+import "math/bits"
+
+// AddMod64 calculates a+b mod m for uint64 numbers without overflows.
+func AddMod64(a, b, m uint64) uint64 {
+  if m == 0 {
+    panic("grypto/modular: modulus must be greater than 0")
+  }
+
+  a, b = a%m, b%m
+  // a+b might overflow, so compare with the distance to m instead
+  if a >= m-b {
+    return a - (m - b)
+  }
+  return a + b
+}
+
+// SubMod64 calculates a-b mod m for uint64 numbers without overflows. The result is always in the range [0, m).
+func SubMod64(a, b, m uint64) uint64 {
+  if m == 0 {
+    panic("grypto/modular: modulus must be greater than 0")
+  }
+
+  a, b = a%m, b%m
+  if a >= b {
+    return a - b
+  }
+  return m - (b - a)
+}
+
+// MulMod64 calculates a*b mod m for uint64 numbers without overflows.
+// In contrast to Pow32, which can widen its int32 operands to int64, there is no larger integer type for the product
+// of two uint64 numbers. Instead, MulMod64 calculates the full 128-bit product using bits.Mul64 and reduces it using
+// bits.Div64, which calculates the remainder of a 128-bit number divided by a 64-bit number.
+func MulMod64(a, b, m uint64) uint64 {
+  if m == 0 {
+    panic("grypto/modular: modulus must be greater than 0")
+  }
+
+  hi, lo := bits.Mul64(a, b)
+  if hi == 0 {
+    return lo % m
+  }
+  // bits.Div64 panics, if the quotient overflows (hi >= m)
+  _, rem := bits.Div64(hi%m, lo, m)
+  return rem
+}
+
+// Pow64 is like Pow32 but for uint64 numbers. It uses MulMod64 for all multiplications, so that the intermediate
+// products don't overflow.
+func Pow64(base, exp, mod uint64) uint64 {
+  if mod == 0 {
+    panic("grypto/modular: modulus must be greater than 0")
+  }
+  if base == 0 && exp == 0 {
+    panic("grypto/modular: 0^0 is not defined")
+  }
+
+  // special cases
+  if base == 0 {
+    return 0
+  }
+  if exp == 0 {
+    return 1
+  }
+
+  // square-and-multiply (fast exponentiation)
+  var (
+    x = uint64(1)
+    a = base % mod
+  )
+
+  for exp > 0 {
+    if exp&1 == 1 {
+      x = MulMod64(x, a, mod) // multiply
+    }
+
+    a = MulMod64(a, a, mod) // square
+    exp >>= 1
+  }
+
+  return x
+}
+
+// gcd64 calculates the greatest common divisor of a and b using Euclid's algorithm (see euclid.GreatestCommonDivisor).
+func gcd64(a, b uint64) uint64 {
+  for b != 0 {
+    a, b = b, a%b
+  }
+  return a
+}
@@ -0,0 +1,126 @@
+package modular_test
+
+import (
+  "math"
+  "math/big"
+  "testing"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/modular"
+)
+
+var _ = Describe("64-bit arithmetic", func() {
+  bigResult := func(f func(z, a, b *big.Int) *big.Int, a, b, m uint64) uint64 {
+    z := f(new(big.Int), new(big.Int).SetUint64(a), new(big.Int).SetUint64(b))
+    return z.Mod(z, new(big.Int).SetUint64(m)).Uint64()
+  }
+
+  values := []uint64{0, 1, 2, 3, 1<<32 - 1, 1 << 32, 1<<63 - 1, 1 << 63, math.MaxUint64 - 1, math.MaxUint64}
+  moduli := []uint64{1, 2, 3, 1<<32 - 5, 1<<61 - 1, 1<<63 + 1, math.MaxUint64 - 58, math.MaxUint64}
+
+  Describe("AddMod64", func() {
+    It("should panic on modulus 0", func() {
+      Expect(func() { modular.AddMod64(1, 1, 0) }).To(Panic())
+    })
+
+    It("should not overflow", func() {
+      for _, m := range moduli {
+        for _, a := range values {
+          for _, b := range values {
+            Expect(modular.AddMod64(a, b, m)).To(Equal(bigResult((*big.Int).Add, a, b, m)), "%d + %d mod %d", a, b, m)
+          }
+        }
+      }
+    })
+  })
+
+  Describe("SubMod64", func() {
+    It("should panic on modulus 0", func() {
+      Expect(func() { modular.SubMod64(1, 1, 0) }).To(Panic())
+    })
+
+    It("should not overflow", func() {
+      for _, m := range moduli {
+        for _, a := range values {
+          for _, b := range values {
+            Expect(modular.SubMod64(a, b, m)).To(Equal(bigResult((*big.Int).Sub, a, b, m)), "%d - %d mod %d", a, b, m)
+          }
+        }
+      }
+    })
+  })
+
+  Describe("MulMod64", func() {
+    It("should panic on modulus 0", func() {
+      Expect(func() { modular.MulMod64(1, 1, 0) }).To(Panic())
+    })
+
+    It("should not overflow", func() {
+      for _, m := range moduli {
+        for _, a := range values {
+          for _, b := range values {
+            Expect(modular.MulMod64(a, b, m)).To(Equal(bigResult((*big.Int).Mul, a, b, m)), "%d * %d mod %d", a, b, m)
+          }
+        }
+      }
+    })
+  })
+
+  Describe("Pow64", func() {
+    It("should panic on invalid inputs", func() {
+      Expect(func() { modular.Pow64(1, 2, 0) }).To(Panic())
+      Expect(func() { modular.Pow64(0, 0, 1) }).To(Panic())
+    })
+
+    It("should return the same results as Pow32", func() {
+      for m := int32(1); m <= 30; m++ {
+        for b := int32(0); b <= m; b++ {
+          for e := int32(0); e <= 40; e++ {
+            if b == 0 && e == 0 {
+              continue
+            }
+            Expect(modular.Pow64(uint64(b), uint64(e), uint64(m))).To(Equal(uint64(modular.Pow32(b, e, m))),
+              "%d ^ %d mod %d", b, e, m)
+          }
+        }
+      }
+    })
+
+    It("should not overflow", func() {
+      for _, m := range moduli {
+        for _, b := range values[1:] {
+          for _, e := range values {
+            if e == 0 {
+              // like Pow32, Pow64 returns 1 for exp=0 regardless of the modulus
+              Expect(modular.Pow64(b, e, m)).To(Equal(uint64(1)))
+              continue
+            }
+            bb, be, bm := new(big.Int).SetUint64(b), new(big.Int).SetUint64(e), new(big.Int).SetUint64(m)
+            expected := new(big.Int).Exp(bb, be, bm)
+            Expect(modular.Pow64(b, e, m)).To(Equal(expected.Uint64()), "%d ^ %d mod %d", b, e, m)
+          }
+        }
+      }
+    })
+  })
+})
+
+var result64 uint64
+
+func BenchmarkPow64MaxInt32(b *testing.B) {
+  var r uint64
+  for n := 0; n < b.N; n++ {
+    r = modular.Pow64(math.MaxInt32-3, math.MaxInt32, math.MaxInt32-1)
+  }
+  result64 = r
+}
+
+func BenchmarkPow64MaxUint64(b *testing.B) {
+  var r uint64
+  for n := 0; n < b.N; n++ {
+    r = modular.Pow64(math.MaxUint64-3, math.MaxUint64, math.MaxUint64-58)
+  }
+  result64 = r
+}
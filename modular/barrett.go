@@ -0,0 +1,90 @@
+package modular
+
+import "math/bits"
+
+// Barrett implements Barrett reduction for a fixed uint64 modulus m.
+// Reducing a number x modulo m requires calculating the quotient q = ⌊x/m⌋, so that x mod m = x - q*m. Division is
+// expensive, so Barrett reduction precomputes the reciprocal of m once as the fixed-point number μ = ⌊2^128/m⌋ and
+// approximates the quotient by q = ⌊x*μ / 2^128⌋, which only needs multiplications and shifts. The approximated
+// quotient is at most 1 smaller than the actual quotient, so at most one final subtraction of m is needed.
+// In contrast to Montgomery reduction, Barrett reduction works for all moduli and doesn't need a special
+// representation of the residues.
+// See https://en.wikipedia.org/wiki/Barrett_reduction.
+type Barrett struct {
+  mod uint64
+  // muHi and muLo are the upper and lower 64 bits of μ = ⌊2^128/m⌋
+  muHi, muLo uint64
+}
+
+// NewBarrett returns a new Barrett context for the given modulus. mod must be greater than 1, otherwise NewBarrett
+// panics.
+func NewBarrett(mod uint64) *Barrett {
+  if mod <= 1 {
+    panic("grypto/modular: modulus must be greater than 1")
+  }
+
+  // long division of 2^128 = 1*2^128 + 0*2^64 + 0 by m, bits.Div64 requires the upper word to be smaller than m
+  muHi, rem := bits.Div64(1, 0, mod)
+  muLo, _ := bits.Div64(rem, 0, mod)
+
+  return &Barrett{
+    mod:  mod,
+    muHi: muHi,
+    muLo: muLo,
+  }
+}
+
+// Modulus returns the modulus of the Barrett context.
+func (br *Barrett) Modulus() uint64 {
+  return br.mod
+}
+
+// Convert reduces a modulo m.
+func (br *Barrett) Convert(a uint64) uint64 {
+  return br.Reduce(0, a)
+}
+
+// Revert returns a unchanged, as Barrett reduction uses the usual representation of residues.
+func (br *Barrett) Revert(a uint64) uint64 {
+  return a
+}
+
+// Reduce calculates x mod m for the 128-bit number x = hi*2^64 + lo using Barrett reduction. x must be smaller than
+// m*2^64 (i.e. hi must be smaller than m), otherwise Reduce panics. This is the case for all products of two residues.
+func (br *Barrett) Reduce(hi, lo uint64) uint64 {
+  if hi >= br.mod {
+    panic("grypto/modular: Barrett input must be smaller than modulus*2^64")
+  }
+
+  // q = ⌊x*μ / 2^128⌋ is the third word of the 256-bit product x*μ, which is the sum of the partial products
+  // hi*muHi*2^128 + (hi*muLo + lo*muHi)*2^64 + lo*muLo. x < m*2^64 ensures that q < 2^64.
+  p0Hi, _ := bits.Mul64(lo, br.muLo)
+  p1Hi, p1Lo := bits.Mul64(lo, br.muHi)
+  p2Hi, p2Lo := bits.Mul64(hi, br.muLo)
+  _, p3Lo := bits.Mul64(hi, br.muHi)
+
+  w1, c1 := bits.Add64(p0Hi, p1Lo, 0)
+  _, c2 := bits.Add64(w1, p2Lo, 0)
+  q := p1Hi + p2Hi + p3Lo + c1 + c2
+
+  // r = x - q*m < 2m might not fit into 64 bits for moduli greater than 2^63
+  qmHi, qmLo := bits.Mul64(q, br.mod)
+  r, borrow := bits.Sub64(lo, qmLo, 0)
+  rHi, _ := bits.Sub64(hi, qmHi, borrow)
+
+  if rHi != 0 || r >= br.mod {
+    r -= br.mod
+  }
+  return r
+}
+
+// Mul calculates a*b mod m using Barrett reduction. a and b must be smaller than m.
+func (br *Barrett) Mul(a, b uint64) uint64 {
+  return br.Reduce(bits.Mul64(a, b))
+}
+
+// Exp calculates base ^ exp mod m using the square-and-multiply method with Barrett reduction.
+// It returns the same results as Pow64.
+func (br *Barrett) Exp(base, exp uint64) uint64 {
+  return PowWith(base, exp, br)
+}
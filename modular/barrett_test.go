@@ -0,0 +1,83 @@
+package modular_test
+
+import (
+  "math"
+  "math/big"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/modular"
+)
+
+var _ = Describe("Barrett", func() {
+  It("should panic on invalid moduli", func() {
+    test := func(m uint64) {
+      ExpectWithOffset(1, func() {
+        modular.NewBarrett(m)
+      }).To(Panic())
+    }
+
+    test(0)
+    test(1)
+  })
+
+  It("should panic on invalid inputs", func() {
+    br := modular.NewBarrett(561)
+    Expect(func() { br.Exp(0, 0) }).To(Panic())
+    Expect(func() { br.Reduce(561, 0) }).To(Panic())
+  })
+
+  moduli := []uint64{
+    2, 3, 4, 561, 1 << 32, math.MaxInt32, 1<<61 - 1, 1 << 63, 1<<63 + 1, math.MaxUint64 - 58, math.MaxUint64,
+  }
+
+  It("should correctly reduce 128-bit numbers", func() {
+    for _, m := range moduli {
+      br := modular.NewBarrett(m)
+      Expect(br.Modulus()).To(Equal(m))
+
+      for _, hi := range []uint64{0, 1, m / 2, m - 1} {
+        for _, lo := range []uint64{0, 1, m - 1, m, math.MaxUint64} {
+          x := new(big.Int).Lsh(new(big.Int).SetUint64(hi), 64)
+          x.Add(x, new(big.Int).SetUint64(lo)).Mod(x, new(big.Int).SetUint64(m))
+          Expect(br.Reduce(hi, lo)).To(Equal(x.Uint64()), "(%d*2^64 + %d) mod %d", hi, lo, m)
+        }
+      }
+    }
+  })
+
+  It("should return the same results as MulMod64", func() {
+    for _, m := range moduli {
+      br := modular.NewBarrett(m)
+      for _, a := range []uint64{0, 1, 2, m / 3, m - 2, m - 1} {
+        for _, b := range []uint64{0, 1, 3, m / 2, m - 2, m - 1} {
+          Expect(br.Mul(a, b)).To(Equal(modular.MulMod64(a, b, m)), "%d * %d mod %d", a, b, m)
+        }
+      }
+    }
+  })
+
+  It("should return the same results as Pow64", func() {
+    for m := uint64(2); m <= 60; m++ {
+      br := modular.NewBarrett(m)
+      for b := uint64(0); b <= m; b++ {
+        for e := uint64(0); e <= 40; e++ {
+          if b == 0 && e == 0 {
+            continue
+          }
+          Expect(br.Exp(b, e)).To(Equal(modular.Pow64(b, e, m)), "%d ^ %d mod %d", b, e, m)
+        }
+      }
+    }
+
+    for _, m := range moduli {
+      br := modular.NewBarrett(m)
+      for _, b := range []uint64{2, 3, m - 1, math.MaxUint64 - 3} {
+        for _, e := range []uint64{1, 2, 65537, m - 1, math.MaxUint64} {
+          Expect(br.Exp(b, e)).To(Equal(modular.Pow64(b, e, m)), "%d ^ %d mod %d", b, e, m)
+        }
+      }
+    }
+  })
+})
@@ -0,0 +1,118 @@
+package modular
+
+import (
+  "fmt"
+  "math"
+
+  "github.com/timebertt/grypto/euclid"
+)
+
+// Congruence represents the congruence x ≡ Residue mod Modulus.
+type Congruence struct {
+  Residue, Modulus int64
+}
+
+func (c Congruence) String() string {
+  return fmt.Sprintf("x ≡ %d mod %d", c.Residue, c.Modulus)
+}
+
+// InconsistentError is returned by CRT, if a system of congruences doesn't have a solution.
+type InconsistentError struct {
+  // A and B are the two contradicting congruences. A might be the combination of multiple previous congruences.
+  A, B Congruence
+  // GCD is the greatest common divisor of both moduli, which doesn't divide the difference of both residues.
+  GCD int64
+}
+
+func (e *InconsistentError) Error() string {
+  return fmt.Sprintf("congruences %s and %s are inconsistent: gcd(%d, %d) = %d does not divide %d - %d",
+    e.A, e.B, e.A.Modulus, e.B.Modulus, e.GCD, e.B.Residue, e.A.Residue)
+}
+
+// CRT solves a system of congruences x ≡ rᵢ mod mᵢ using the chinese remainder theorem. It returns the solution as
+// a single congruence x ≡ r mod m, where m is the least common multiple of all moduli and 0 <= r < m.
+// If the moduli are pairwise coprime, the chinese remainder theorem guarantees that there is exactly one solution
+// modulo m = m₁ * ... * mₖ. Otherwise, the system only has a solution if it is consistent, i.e. if
+// rᵢ ≡ rⱼ mod gcd(mᵢ, mⱼ) for all i, j. If it is inconsistent, CRT returns an *InconsistentError.
+// The congruences are combined one after another: x ≡ r₁ mod m₁ and x ≡ r₂ mod m₂ are solved by x = r₁ + m₁*t,
+// where t solves m₁*t ≡ r₂ - r₁ mod m₂. With g = gcd(m₁, m₂), this congruence is solvable if and only if g divides
+// r₂ - r₁. Then t ≡ (r₂ - r₁)/g * (m₁/g)⁻¹ mod m₂/g and x is unique modulo lcm(m₁, m₂) = m₁ * m₂/g.
+// All moduli must be greater than 0 and their least common multiple must fit into int64, otherwise CRT panics.
+// See https://en.wikipedia.org/wiki/Chinese_remainder_theorem.
+func CRT(congruences []Congruence) (Congruence, error) {
+  return CRTTrace(congruences, nil)
+}
+
+// CRTTrace is like CRT but calls trace for each combination of two congruences.
+func CRTTrace(congruences []Congruence, trace TraceFunc) (Congruence, error) {
+  for _, c := range congruences {
+    if c.Modulus <= 0 {
+      panic("grypto/modular: modulus must be greater than 0")
+    }
+  }
+
+  // x ≡ 0 mod 1 is satisfied by all integers
+  result := Congruence{Residue: 0, Modulus: 1}
+
+  for i, c := range congruences {
+    c = normalizeCongruence(c)
+    if i == 0 {
+      result = c
+      trace.printf("%s", result)
+      continue
+    }
+
+    var err error
+    result, err = combineCongruences(result, c, trace)
+    if err != nil {
+      return Congruence{}, err
+    }
+  }
+
+  return result, nil
+}
+
+// normalizeCongruence reduces the residue of c to the range [0, m).
+func normalizeCongruence(c Congruence) Congruence {
+  c.Residue %= c.Modulus
+  if c.Residue < 0 {
+    c.Residue += c.Modulus
+  }
+  return c
+}
+
+// combineCongruences solves x ≡ a.Residue mod a.Modulus and x ≡ b.Residue mod b.Modulus, both residues must be
+// normalized.
+func combineCongruences(a, b Congruence, trace TraceFunc) (Congruence, error) {
+  trace.printf("combine %s and %s", a, b)
+
+  g := int64(euclid.GreatestCommonDivisor(int(a.Modulus), int(b.Modulus)))
+  // difference of the residues modulo b.Modulus, x = a.Residue + a.Modulus*t needs a.Modulus*t ≡ d mod b.Modulus
+  d := b.Residue - a.Residue
+  trace.printf("  x = %d + %d*t, %d*t ≡ %d mod %d, gcd(%d, %d) = %d", a.Residue, a.Modulus, a.Modulus, d, b.Modulus,
+    a.Modulus, b.Modulus, g)
+
+  if d%g != 0 {
+    trace.printf("  %d does not divide %d => no solution", g, d)
+    return Congruence{}, &InconsistentError{A: a, B: b, GCD: g}
+  }
+
+  m1, m2 := a.Modulus/g, b.Modulus/g
+  if m1 > math.MaxInt64/b.Modulus {
+    panic("grypto/modular: least common multiple of moduli overflows int64")
+  }
+  lcm := m1 * b.Modulus
+
+  // t ≡ d/g * (m₁/g)⁻¹ mod m₂/g, m₁/g and m₂/g are coprime
+  inv, _ := Inverse64(m1, m2)
+  dg := (d/g%m2 + m2) % m2
+  t := int64(MulMod64(uint64(dg), uint64(inv), uint64(m2)))
+  trace.printf("  t ≡ %d mod %d", t, m2)
+
+  // x = r₁ + m₁*t < m₁ + lcm doesn't overflow uint64
+  x := (uint64(a.Residue) + uint64(a.Modulus)*uint64(t)) % uint64(lcm)
+  result := Congruence{Residue: int64(x), Modulus: lcm}
+  trace.printf("  => %s", result)
+
+  return result, nil
+}
@@ -0,0 +1,116 @@
+package modular_test
+
+import (
+  "fmt"
+  "math"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/modular"
+)
+
+var _ = Describe("CRT", func() {
+  type c = modular.Congruence
+
+  It("should panic on invalid moduli", func() {
+    test := func(congruences ...modular.Congruence) {
+      ExpectWithOffset(1, func() {
+        _, _ = modular.CRT(congruences)
+      }).To(Panic())
+    }
+
+    test(c{1, 0})
+    test(c{1, 3}, c{1, -5})
+    // lcm overflows int64
+    test(c{1, math.MaxInt64}, c{1, math.MaxInt64 - 1})
+  })
+
+  It("should solve systems with pairwise coprime moduli", func() {
+    test := func(expected modular.Congruence, congruences ...modular.Congruence) {
+      solution, err := modular.CRT(congruences)
+      ExpectWithOffset(1, err).NotTo(HaveOccurred())
+      ExpectWithOffset(1, solution).To(Equal(expected))
+    }
+
+    test(c{0, 1})
+    test(c{2, 3}, c{2, 3})
+    test(c{2, 3}, c{5, 3})
+    test(c{1, 3}, c{-2, 3})
+    test(c{23, 105}, c{2, 3}, c{3, 5}, c{2, 7})
+    test(c{1000, 1021 * 1031 * 1033}, c{1000, 1021}, c{1000, 1031}, c{1000, 1033})
+    test(c{0, 30}, c{0, 2}, c{0, 3}, c{0, 5})
+    test(c{math.MaxInt64 - 1, math.MaxInt64}, c{math.MaxInt64 - 1, math.MaxInt64}, c{0, 1})
+  })
+
+  It("should solve consistent systems with non-coprime moduli", func() {
+    test := func(expected modular.Congruence, congruences ...modular.Congruence) {
+      solution, err := modular.CRT(congruences)
+      ExpectWithOffset(1, err).NotTo(HaveOccurred())
+      ExpectWithOffset(1, solution).To(Equal(expected))
+    }
+
+    test(c{3, 12}, c{3, 4}, c{3, 6})
+    test(c{10, 12}, c{2, 4}, c{4, 6})
+    test(c{5, 12}, c{1, 4}, c{5, 6}, c{2, 3})
+    test(c{2, 8}, c{2, 8}, c{2, 4}, c{0, 2})
+  })
+
+  It("should verify all solutions", func() {
+    for m1 := int64(1); m1 <= 12; m1++ {
+      for m2 := int64(1); m2 <= 12; m2++ {
+        for r1 := int64(0); r1 < m1; r1++ {
+          for r2 := int64(0); r2 < m2; r2++ {
+            solution, err := modular.CRT([]modular.Congruence{{r1, m1}, {r2, m2}})
+
+            // find the smallest solution by enumeration
+            exists := false
+            for x := int64(0); x < m1*m2; x++ {
+              if x%m1 == r1 && x%m2 == r2 {
+                exists = true
+                Expect(err).NotTo(HaveOccurred())
+                Expect(solution.Residue).To(Equal(x))
+                break
+              }
+            }
+            if !exists {
+              Expect(err).To(BeAssignableToTypeOf(&modular.InconsistentError{}))
+            }
+          }
+        }
+      }
+    }
+  })
+
+  It("should return an error for inconsistent systems", func() {
+    _, err := modular.CRT([]modular.Congruence{{1, 4}, {2, 6}})
+    Expect(err).To(Equal(&modular.InconsistentError{A: c{1, 4}, B: c{2, 6}, GCD: 2}))
+    Expect(err).To(MatchError("congruences x ≡ 1 mod 4 and x ≡ 2 mod 6 are inconsistent: " +
+      "gcd(4, 6) = 2 does not divide 2 - 1"))
+
+    _, err = modular.CRT([]modular.Congruence{{2, 3}, {3, 5}, {1, 15}})
+    Expect(err).To(Equal(&modular.InconsistentError{A: c{8, 15}, B: c{1, 15}, GCD: 15}))
+  })
+
+  It("should trace all steps", func() {
+    var lines []string
+    trace := func(format string, a ...interface{}) {
+      lines = append(lines, fmt.Sprintf(format, a...))
+    }
+
+    solution, err := modular.CRTTrace([]modular.Congruence{{2, 3}, {3, 5}, {2, 7}}, trace)
+    Expect(err).NotTo(HaveOccurred())
+    Expect(solution).To(Equal(c{23, 105}))
+    Expect(lines).To(Equal([]string{
+      "x ≡ 2 mod 3",
+      "combine x ≡ 2 mod 3 and x ≡ 3 mod 5",
+      "  x = 2 + 3*t, 3*t ≡ 1 mod 5, gcd(3, 5) = 1",
+      "  t ≡ 2 mod 5",
+      "  => x ≡ 8 mod 15",
+      "combine x ≡ 8 mod 15 and x ≡ 2 mod 7",
+      "  x = 8 + 15*t, 15*t ≡ -6 mod 7, gcd(15, 7) = 1",
+      "  t ≡ 1 mod 7",
+      "  => x ≡ 23 mod 105",
+    }))
+  })
+})
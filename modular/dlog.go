@@ -1,5 +1,7 @@
 package modular
 
+import "math/big"
+
 // DLogMaxMod is the maximum mod value accepted in DLog32.
 const DLogMaxMod = 1 << 22
 
@@ -52,3 +54,43 @@ func DLog32(x, base, mod int32) (dlog int32, exists bool) {
 
   return 0, false
 }
+
+// DLogBig is like DLog32 but for arbitrarily large integers. Note that the modulus is still limited to DLogMaxMod,
+// as the enumeration is impractical for larger moduli. Use DLogBabyStepGiantStepBig instead.
+func DLogBig(x, base, mod *big.Int) (dlog *big.Int, exists bool) {
+  if x.Sign() <= 0 {
+    panic("grypto/modular: x must be greater than 0")
+  }
+  if base.Sign() <= 0 {
+    panic("grypto/modular: base must be greater than 0")
+  }
+  if mod.Sign() <= 0 {
+    panic("grypto/modular: modulus must be greater than 0")
+  }
+  if mod.Cmp(big.NewInt(DLogMaxMod)) > 0 {
+    panic("grypto/modular: modulus too large")
+  }
+
+  var (
+    exp = big.NewInt(1)
+    b   = new(big.Int).Mod(base, mod)
+    one = big.NewInt(1)
+  )
+
+  for dlog = new(big.Int); dlog.Cmp(mod) < 0; dlog.Add(dlog, one) {
+    if exp.Cmp(x) == 0 {
+      return dlog, true
+    }
+
+    if dlog.Sign() > 0 && exp.Cmp(one) == 0 {
+      break
+    }
+    if exp.Sign() == 0 {
+      return nil, false
+    }
+
+    exp.Mul(exp, b).Mod(exp, mod)
+  }
+
+  return nil, false
+}
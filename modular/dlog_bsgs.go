@@ -0,0 +1,186 @@
+package modular
+
+import (
+  "math/big"
+
+  "github.com/timebertt/grypto/euclid"
+)
+
+// DLogBabyStepGiantStep32 calculates the discrete logarithm of x to the given base and modulus using the
+// baby-step giant-step algorithm for int32 numbers. It returns the same results as DLog32 (the smallest y, so that
+// b^y ≡ x mod m), but doesn't limit the modulus to DLogMaxMod.
+// Instead of enumerating all exponents, the algorithm writes the exponent as y = n*p - q with n = ⌈√m⌉ and
+// 0 <= q < n. It first calculates and stores the "baby steps" x*b^q for all q, and then calculates the "giant steps"
+// b^(n*p) for p=1,2,...,n until one of them matches a stored baby step. This reduces the time complexity to O(√m)
+// at the cost of memory in O(√m).
+// If base and modulus are not coprime, the common factors are divided out first (extended baby-step giant-step).
+// See https://en.wikipedia.org/wiki/Baby-step_giant-step.
+func DLogBabyStepGiantStep32(x, base, mod int32) (dlog int32, exists bool) {
+  if x <= 0 {
+    panic("grypto/modular: x must be greater than 0")
+  }
+  if base <= 0 {
+    panic("grypto/modular: base must be greater than 0")
+  }
+  if mod <= 0 {
+    panic("grypto/modular: modulus must be greater than 0")
+  }
+
+  if x == 1 {
+    return 0, true
+  }
+  if x >= mod {
+    return 0, false
+  }
+
+  var (
+    b = int64(base % mod)
+    y = int64(x)
+    m = int64(mod)
+    // k accumulates the factors divided out of b^add, so that we search for k*b^(dlog-add) ≡ y mod m
+    k   = int64(1)
+    add = int64(0)
+  )
+
+  for {
+    g := int64(euclid.GreatestCommonDivisor(int(b), int(m)))
+    if g == 1 {
+      break
+    }
+
+    if y == k {
+      return int32(add), true
+    }
+    if y%g != 0 {
+      return 0, false
+    }
+    y /= g
+    m /= g
+    add++
+    k = k * (b / g) % m
+    b %= m
+  }
+
+  if dlog, ok := babyStepGiantStep(y%m, b, k%m, m, m); ok {
+    return int32(dlog + add), true
+  }
+  return 0, false
+}
+
+// babyStepGiantStep searches the smallest 0 <= z <= n, so that k*b^z ≡ y mod m, using ⌈√n⌉ baby and giant steps.
+// b must be coprime to m, n should be chosen as the order of b (or a multiple of it).
+func babyStepGiantStep(y, b, k, m, n int64) (z int64, exists bool) {
+  if y == k {
+    return 0, true
+  }
+
+  steps := int64(1)
+  for steps*steps < n {
+    steps++
+  }
+
+  // baby steps: y*b^q for q=0,...,steps-1, later q overwrite earlier ones, so that we find the smallest dlog
+  babySteps := make(map[int64]int64, steps)
+  cur := y
+  for q := int64(0); q < steps; q++ {
+    babySteps[cur] = q
+    cur = cur * b % m
+  }
+
+  // giant steps: k*b^(steps*p) for p=1,...,steps
+  giant := int64(1)
+  for i := int64(0); i < steps; i++ {
+    giant = giant * b % m
+  }
+  cur = k
+  for p := int64(1); p <= steps; p++ {
+    cur = cur * giant % m
+    if q, ok := babySteps[cur]; ok {
+      return steps*p - q, true
+    }
+  }
+
+  return 0, false
+}
+
+// DLogBabyStepGiantStepBig is like DLogBabyStepGiantStep32 but for arbitrarily large numbers.
+// Note that the memory needed by the algorithm is in O(√m), so it is only practicable for moduli up to about 2^40.
+func DLogBabyStepGiantStepBig(x, base, mod *big.Int) (dlog *big.Int, exists bool) {
+  if x.Sign() <= 0 {
+    panic("grypto/modular: x must be greater than 0")
+  }
+  if base.Sign() <= 0 {
+    panic("grypto/modular: base must be greater than 0")
+  }
+  if mod.Sign() <= 0 {
+    panic("grypto/modular: modulus must be greater than 0")
+  }
+
+  one := big.NewInt(1)
+  if x.Cmp(one) == 0 {
+    return new(big.Int), true
+  }
+  if x.Cmp(mod) >= 0 {
+    return nil, false
+  }
+
+  var (
+    b   = new(big.Int).Mod(base, mod)
+    y   = new(big.Int).Set(x)
+    m   = new(big.Int).Set(mod)
+    k   = big.NewInt(1)
+    add = new(big.Int)
+    g   = new(big.Int)
+    r   = new(big.Int)
+  )
+
+  for g.GCD(nil, nil, b, m).Cmp(one) > 0 {
+    if y.Cmp(k) == 0 {
+      return add, true
+    }
+    if r.Mod(y, g).Sign() != 0 {
+      return nil, false
+    }
+    y.Quo(y, g)
+    m.Quo(m, g)
+    add.Add(add, one)
+    k.Mul(k, r.Quo(b, g)).Mod(k, m)
+    b.Mod(b, m)
+  }
+
+  y.Mod(y, m)
+  k.Mod(k, m)
+  if y.Cmp(k) == 0 {
+    return add, true
+  }
+
+  // n = ⌈√m⌉
+  n := new(big.Int).Sqrt(m)
+  if r.Mul(n, n).Cmp(m) < 0 {
+    n.Add(n, one)
+  }
+  if !n.IsInt64() {
+    panic("grypto/modular: modulus too large")
+  }
+  steps := n.Int64()
+
+  babySteps := make(map[string]int64, steps)
+  cur := new(big.Int).Set(y)
+  for q := int64(0); q < steps; q++ {
+    babySteps[string(cur.Bytes())] = q
+    cur.Mul(cur, b).Mod(cur, m)
+  }
+
+  giant := new(big.Int).Exp(b, n, m)
+  cur.Set(k)
+  for p := int64(1); p <= steps; p++ {
+    cur.Mul(cur, giant).Mod(cur, m)
+    if q, ok := babySteps[string(cur.Bytes())]; ok {
+      dlog = new(big.Int).Mul(n, big.NewInt(p))
+      dlog.Sub(dlog, big.NewInt(q))
+      return dlog.Add(dlog, add), true
+    }
+  }
+
+  return nil, false
+}
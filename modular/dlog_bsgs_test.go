@@ -0,0 +1,125 @@
+package modular_test
+
+import (
+  "math"
+  "math/big"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/modular"
+)
+
+var _ = Describe("DLogBabyStepGiantStep32", func() {
+  It("should panic on invalid inputs", func() {
+    test := func(x, b, m int32) {
+      ExpectWithOffset(1, func() {
+        modular.DLogBabyStepGiantStep32(x, b, m)
+      }).To(Panic())
+    }
+
+    test(0, 1, 2)
+    test(-1, 1, 2)
+    test(1, -1, 2)
+    test(1, 1, -1)
+    test(1, 0, 2)
+    test(1, 1, 0)
+  })
+
+  It("should correctly calculate dlog", func() {
+    test := func(x, b, m, expected int32, expectedExists bool) {
+      s, ok := modular.DLogBabyStepGiantStep32(x, b, m)
+      ExpectWithOffset(1, ok).To(Equal(expectedExists))
+      if expectedExists {
+        ExpectWithOffset(1, s).To(Equal(expected))
+      }
+    }
+
+    test(1, 1, 13, 0, true)
+    test(1, 2, 13, 0, true)
+    test(3, 2, 13, 4, true)
+    test(5, 2, 13, 9, true)
+    test(9, 2, 13, 8, true)
+    test(12, 2, 13, 6, true)
+    test(11, 2, 13, 7, true)
+
+    test(2, 1, 13, 0, false)
+    test(3, 2, 8, 0, false)
+    test(4, 2, 8, 2, true)
+    test(8, 2, 12, 3, true)
+    test(13, 2, 13, 0, false)
+  })
+
+  It("should return the same results as DLog32", func() {
+    for m := int32(1); m <= 64; m++ {
+      for b := int32(1); b <= m; b++ {
+        for x := int32(1); x <= m; x++ {
+          expected, expectedExists := modular.DLog32(x, b, m)
+          s, ok := modular.DLogBabyStepGiantStep32(x, b, m)
+          Expect(ok).To(Equal(expectedExists), "dlog(%d) to the base %d mod %d", x, b, m)
+          Expect(s).To(Equal(expected), "dlog(%d) to the base %d mod %d", x, b, m)
+        }
+      }
+    }
+  })
+
+  It("should calculate dlog for moduli larger than DLogMaxMod", func() {
+    test := func(x, b, m int32) {
+      s, ok := modular.DLogBabyStepGiantStep32(x, b, m)
+      ExpectWithOffset(1, ok).To(BeTrue())
+      ExpectWithOffset(1, modular.Pow32(b, s, m)).To(Equal(x))
+    }
+
+    // 7 is a primitive root mod 2^31-1
+    test(2, 7, math.MaxInt32)
+    test(math.MaxInt32-1, 7, math.MaxInt32)
+    test(123456789, 7, math.MaxInt32)
+    test(16807, 7, math.MaxInt32)
+    test(1<<30, 2, math.MaxInt32-1)
+
+    s, ok := modular.DLogBabyStepGiantStep32(16807, 7, math.MaxInt32)
+    Expect(ok).To(BeTrue())
+    Expect(s).To(Equal(int32(5)))
+  })
+})
+
+var _ = Describe("DLogBabyStepGiantStepBig", func() {
+  It("should panic on invalid inputs", func() {
+    test := func(x, b, m int64) {
+      ExpectWithOffset(1, func() {
+        modular.DLogBabyStepGiantStepBig(big.NewInt(x), big.NewInt(b), big.NewInt(m))
+      }).To(Panic())
+    }
+
+    test(0, 1, 2)
+    test(1, -1, 2)
+    test(1, 1, 0)
+  })
+
+  It("should return the same results as DLog32", func() {
+    for m := int32(1); m <= 40; m++ {
+      for b := int32(1); b <= m; b++ {
+        for x := int32(1); x <= m; x++ {
+          expected, expectedExists := modular.DLog32(x, b, m)
+          s, ok := modular.DLogBabyStepGiantStepBig(big.NewInt(int64(x)), big.NewInt(int64(b)), big.NewInt(int64(m)))
+          Expect(ok).To(Equal(expectedExists), "dlog(%d) to the base %d mod %d", x, b, m)
+          if expectedExists {
+            Expect(s.Int64()).To(Equal(int64(expected)), "dlog(%d) to the base %d mod %d", x, b, m)
+          }
+        }
+      }
+    }
+  })
+
+  It("should calculate dlog for moduli larger than int32", func() {
+    // 2^40 - 87 is prime and 13 is a primitive root
+    m, _ := new(big.Int).SetString("1099511627689", 10)
+    b := big.NewInt(13)
+    e := big.NewInt(987654321123)
+    x := new(big.Int).Exp(b, e, m)
+
+    s, ok := modular.DLogBabyStepGiantStepBig(x, b, m)
+    Expect(ok).To(BeTrue())
+    Expect(new(big.Int).Exp(b, s, m)).To(Equal(x))
+  })
+})
@@ -0,0 +1,341 @@
+package modular
+
+import (
+  "math"
+  "math/rand"
+
+  "github.com/timebertt/grypto/euclid"
+)
+
+const (
+  // indexCalculusExtraRelations is the number of relations collected in addition to the size of the factor base,
+  // so that the linear system most likely has full rank.
+  indexCalculusExtraRelations = 20
+  // indexCalculusMaxPrime is the maximum prime modulus accepted by DLogIndexCalculus64.
+  indexCalculusMaxPrime = 1 << 62
+)
+
+// DLogIndexCalculus64 calculates the discrete logarithm of x to the given base modulo a prime p using the index
+// calculus algorithm for uint64 numbers. It returns the same results as DLog32 (the smallest y, so that
+// b^y ≡ x mod p). p must be a prime, otherwise DLogIndexCalculus64 panics.
+// In contrast to the generic algorithms (baby-step giant-step, Pollard's rho), index calculus makes use of the
+// structure of ℤₚ: it has a subexponential runtime, which makes it practicable for primes up to about 40 bits.
+// The algorithm works in three steps based on a generator γ of ℤₚ* and a factor base of small primes q₁, ..., qₖ:
+//  1. Collect relations: find random exponents e, so that γ^e mod p is smooth, i.e. it factors completely over the
+//     factor base. Each relation γ^e ≡ q₁^a₁ * ... * qₖ^aₖ mod p yields a linear equation for the logarithms of
+//     the factor base: e ≡ a₁*log(q₁) + ... + aₖ*log(qₖ) mod p-1.
+//  2. Solve the linear system modulo p-1 for the logarithms of the factor base.
+//  3. Individual log: find a random exponent s, so that x*γ^s mod p is smooth, then
+//     log(x) ≡ a₁*log(q₁) + ... + aₖ*log(qₖ) - s mod p-1.
+//
+// The logarithms to the base γ of x and b are finally used to solve log(b) * y ≡ log(x) mod p-1.
+// See https://en.wikipedia.org/wiki/Index_calculus_algorithm.
+func DLogIndexCalculus64(x, base, p uint64) (dlog uint64, exists bool) {
+  if x == 0 {
+    panic("grypto/modular: x must be greater than 0")
+  }
+  if base == 0 {
+    panic("grypto/modular: base must be greater than 0")
+  }
+  if p > indexCalculusMaxPrime {
+    panic("grypto/modular: modulus too large")
+  }
+  if !isPrime64(p) {
+    // the relation search never ends for composite moduli
+    panic("grypto/modular: modulus must be a prime")
+  }
+
+  if x == 1 {
+    return 0, true
+  }
+  if x >= p {
+    return 0, false
+  }
+  base %= p
+  if base == 0 {
+    return 0, false
+  }
+
+  // ℤₚ* is cyclic of order n = p-1
+  n := p - 1
+  factors := Factorize(n)
+  gamma := primitiveRoot(p, factors)
+
+  ic := &indexCalculus{
+    p:          p,
+    gamma:      gamma,
+    factors:    factors,
+    factorBase: factorBase(p),
+    // use a fixed seed, so that results are reproducible
+    rnd: rand.New(rand.NewSource(1)),
+  }
+  ic.solveFactorBase()
+
+  // solve log(b) * y ≡ log(x) mod n
+  logBase, logX := ic.log(base), ic.log(x)
+  g := uint64(euclid.GreatestCommonDivisor(int(logBase), int(n)))
+  if logX%g != 0 {
+    return 0, false
+  }
+
+  // the order of b is n/g, so the smallest solution is the unique solution modulo n/g
+  n /= g
+  inv, _ := Inverse64(int64(logBase/g%n), int64(n))
+  return MulMod64(logX/g, uint64(inv), n), true
+}
+
+type indexCalculus struct {
+  p, gamma uint64
+  // factors is the factorization of p-1
+  factors    []PrimePower
+  factorBase []uint64
+  // logs holds the logarithms of the factor base to the base gamma
+  logs []uint64
+
+  rnd *rand.Rand
+}
+
+// primitiveRoot finds the smallest generator of ℤₚ*, i.e. the smallest g, so that g^((p-1)/q) ≢ 1 mod p for all
+// prime factors q of p-1.
+func primitiveRoot(p uint64, factors []PrimePower) uint64 {
+  for g := uint64(1); g < p; g++ {
+    isGenerator := true
+    for _, f := range factors {
+      if Pow64(g, (p-1)/f.Prime, p) == 1 {
+        isGenerator = false
+        break
+      }
+    }
+    if isGenerator {
+      return g
+    }
+  }
+
+  panic("grypto/modular: no primitive root found, modulus is not a prime")
+}
+
+// indexCalculusPrimeBases are the bases used by isPrime64. Jim Sinclair's set of 7 bases is the smallest known set,
+// for which the Miller-Rabin primality test is exact for all uint64 numbers, and hence for all moduli up to
+// indexCalculusMaxPrime. No set of 6 bases is known to be exact up to indexCalculusMaxPrime.
+var indexCalculusPrimeBases = []uint64{2, 325, 9375, 28178, 450775, 9780504, 1795265022}
+
+// isPrime64 tests p for primality using the deterministic Miller-Rabin primality test with indexCalculusPrimeBases.
+// This copy of prime.IsPrime64 exists to avoid an import cycle, as package prime imports package modular.
+func isPrime64(p uint64) bool {
+  if p < 2 {
+    return false
+  }
+  if p < 4 {
+    return true
+  }
+  if p%2 == 0 {
+    return false
+  }
+
+  // write p-1 as 2^s * d with d odd
+  s, d := 0, p-1
+  for d%2 == 0 {
+    s++
+    d /= 2
+  }
+
+outer:
+  for _, a := range indexCalculusPrimeBases {
+    a %= p
+    if a == 0 {
+      // a multiple of p can't be used as a witness
+      continue
+    }
+
+    ad := Pow64(a, d, p)
+    if ad == 1 || ad == p-1 {
+      continue
+    }
+    for r := 1; r < s; r++ {
+      ad = MulMod64(ad, ad, p)
+      if ad == p-1 {
+        continue outer
+      }
+    }
+    return false
+  }
+  return true
+}
+
+// factorBase returns all primes smaller than the smoothness bound B = exp(√(ln p * ln ln p) / 2) * 2, which is a good
+// tradeoff between the number of needed relations and the probability of finding them.
+func factorBase(p uint64) []uint64 {
+  bound := uint64(20)
+  if lnP := math.Log(float64(p)); lnP > 1 {
+    if b := uint64(2 * math.Exp(math.Sqrt(lnP*math.Log(lnP))/2)); b > bound {
+      bound = b
+    }
+  }
+  if bound >= p {
+    bound = p - 1
+  }
+
+  // sieve of Eratosthenes
+  composite := make([]bool, bound+1)
+  var primes []uint64
+  for i := uint64(2); i <= bound; i++ {
+    if composite[i] {
+      continue
+    }
+    primes = append(primes, i)
+    for j := i * i; j <= bound; j += i {
+      composite[j] = true
+    }
+  }
+
+  return primes
+}
+
+// factorSmooth factors v over the factor base. It returns false, if v is not smooth.
+func (ic *indexCalculus) factorSmooth(v uint64) ([]uint64, bool) {
+  exponents := make([]uint64, len(ic.factorBase))
+  for i, q := range ic.factorBase {
+    for v%q == 0 {
+      v /= q
+      exponents[i]++
+    }
+    if v == 1 {
+      return exponents, true
+    }
+  }
+  return nil, v == 1
+}
+
+// solveFactorBase calculates the logarithms of the factor base to the base gamma. It collects relations until the
+// linear system can be solved modulo each prime power dividing p-1.
+func (ic *indexCalculus) solveFactorBase() {
+  var (
+    n         = ic.p - 1
+    k         = len(ic.factorBase)
+    relations [][]uint64
+    rhs       []uint64
+  )
+
+  for want := k + indexCalculusExtraRelations; ; want += indexCalculusExtraRelations {
+    for len(relations) < want {
+      e := 1 + uint64(ic.rnd.Int63n(int64(n)))
+      if exponents, ok := ic.factorSmooth(Pow64(ic.gamma, e, ic.p)); ok {
+        relations = append(relations, exponents)
+        rhs = append(rhs, e%n)
+      }
+    }
+
+    if ic.solveLinearSystem(relations, rhs) {
+      return
+    }
+    // the system doesn't have full rank modulo some prime factor of p-1, collect more relations
+  }
+}
+
+// solveLinearSystem solves the linear system modulo each prime power dividing p-1 and combines the results using the
+// chinese remainder theorem. It returns false, if the system can't be solved uniquely.
+func (ic *indexCalculus) solveLinearSystem(relations [][]uint64, rhs []uint64) bool {
+  var (
+    k           = len(ic.factorBase)
+    congruences = make([][]Congruence, k)
+  )
+
+  for _, f := range ic.factors {
+    solution, ok := solveModPrimePower(relations, rhs, f.Prime, f.Value())
+    if !ok {
+      return false
+    }
+    for j := range solution {
+      congruences[j] = append(congruences[j], Congruence{Residue: int64(solution[j]), Modulus: int64(f.Value())})
+    }
+  }
+
+  ic.logs = make([]uint64, k)
+  for j := range ic.logs {
+    // the moduli are pairwise coprime, so the system always has a solution
+    solution, _ := CRT(congruences[j])
+    ic.logs[j] = uint64(solution.Residue)
+    if Pow64(ic.gamma, ic.logs[j], ic.p) != ic.factorBase[j] {
+      return false
+    }
+  }
+  return true
+}
+
+// solveModPrimePower solves the linear system a*x ≡ b mod q^e using gaussian elimination. As ℤ_(q^e) is not a field,
+// only elements not divisible by q are chosen as pivots (they are invertible). It returns false, if the system doesn't
+// have full rank.
+func solveModPrimePower(a [][]uint64, b []uint64, q, m uint64) ([]uint64, bool) {
+  var (
+    rows = len(a)
+    cols = len(a[0])
+    // augmented matrix (a|b) modulo m
+    matrix = make([][]uint64, rows)
+  )
+
+  for i := range a {
+    matrix[i] = make([]uint64, cols+1)
+    for j := range a[i] {
+      matrix[i][j] = a[i][j] % m
+    }
+    matrix[i][cols] = b[i] % m
+  }
+
+  for col := 0; col < cols; col++ {
+    pivot := -1
+    for i := col; i < rows; i++ {
+      if matrix[i][col]%q != 0 {
+        pivot = i
+        break
+      }
+    }
+    if pivot < 0 {
+      return nil, false
+    }
+    matrix[col], matrix[pivot] = matrix[pivot], matrix[col]
+
+    // normalize pivot row
+    inv, _ := Inverse64(int64(matrix[col][col]), int64(m))
+    factor := uint64(inv)
+    for j := col; j <= cols; j++ {
+      matrix[col][j] = MulMod64(matrix[col][j], factor, m)
+    }
+
+    // eliminate column in all other rows
+    for i := 0; i < rows; i++ {
+      if i == col || matrix[i][col] == 0 {
+        continue
+      }
+      factor := matrix[i][col]
+      for j := col; j <= cols; j++ {
+        matrix[i][j] = SubMod64(matrix[i][j], MulMod64(factor, matrix[col][j], m), m)
+      }
+    }
+  }
+
+  solution := make([]uint64, cols)
+  for j := range solution {
+    solution[j] = matrix[j][cols]
+  }
+  return solution, true
+}
+
+// log calculates the logarithm of x to the base gamma using the logarithms of the factor base.
+func (ic *indexCalculus) log(x uint64) uint64 {
+  n := ic.p - 1
+
+  for {
+    // x*γ^s ≡ q₁^a₁ * ... * qₖ^aₖ => log(x) ≡ a₁*log(q₁) + ... + aₖ*log(qₖ) - s
+    s := uint64(ic.rnd.Int63n(int64(n)))
+    exponents, ok := ic.factorSmooth(MulMod64(x, Pow64(ic.gamma, s, ic.p), ic.p))
+    if !ok {
+      continue
+    }
+
+    l := n - s
+    for j, e := range exponents {
+      l = AddMod64(l, MulMod64(e, ic.logs[j], n), n)
+    }
+    return l
+  }
+}
@@ -0,0 +1,104 @@
+package modular_test
+
+import (
+  "math/big"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/modular"
+)
+
+var _ = Describe("DLogIndexCalculus64", func() {
+  It("should panic on invalid inputs", func() {
+    test := func(x, b, p uint64) {
+      ExpectWithOffset(1, func() {
+        modular.DLogIndexCalculus64(x, b, p)
+      }).To(Panic())
+    }
+
+    test(0, 1, 2)
+    test(1, 0, 2)
+    test(1, 1, 0)
+    test(1, 1, 1)
+    test(1, 1, 1<<63)
+    // composite moduli
+    test(2, 3, 91)
+    test(4, 2, 12)
+    test(2, 3, 2047)
+    test(2, 3, 4759123141)
+  })
+
+  It("should correctly calculate dlog", func() {
+    test := func(x, b, p, expected uint64, expectedExists bool) {
+      s, ok := modular.DLogIndexCalculus64(x, b, p)
+      ExpectWithOffset(1, ok).To(Equal(expectedExists))
+      if expectedExists {
+        ExpectWithOffset(1, s).To(Equal(expected))
+      }
+    }
+
+    test(1, 1, 13, 0, true)
+    test(1, 2, 13, 0, true)
+    test(3, 2, 13, 4, true)
+    test(5, 2, 13, 9, true)
+    test(9, 2, 13, 8, true)
+    test(12, 2, 13, 6, true)
+    test(11, 2, 13, 7, true)
+
+    test(2, 1, 13, 0, false)
+    test(13, 2, 13, 0, false)
+    test(2, 13, 13, 0, false)
+  })
+
+  It("should return the same results as DLog32", func() {
+    test := func(p, bStep int32) {
+      for b := int32(1); b < p; b += bStep {
+        for x := int32(1); x < p; x++ {
+          expected, expectedExists := modular.DLog32(x, b, p)
+          s, ok := modular.DLogIndexCalculus64(uint64(x), uint64(b), uint64(p))
+          ExpectWithOffset(1, ok).To(Equal(expectedExists), "dlog(%d) to the base %d mod %d", x, b, p)
+          ExpectWithOffset(1, s).To(Equal(uint64(expected)), "dlog(%d) to the base %d mod %d", x, b, p)
+        }
+      }
+    }
+
+    for _, p := range []int32{2, 3, 5, 7, 11, 13, 31, 97} {
+      test(p, 1)
+    }
+    // larger primes, test only some of the bases for speed
+    test(509, 31)
+  })
+
+  It("should return the same results as DLogBabyStepGiantStep32", func() {
+    test := func(x, b, p int32) {
+      expected, expectedExists := modular.DLogBabyStepGiantStep32(x, b, p)
+      s, ok := modular.DLogIndexCalculus64(uint64(x), uint64(b), uint64(p))
+      ExpectWithOffset(1, ok).To(Equal(expectedExists))
+      ExpectWithOffset(1, s).To(Equal(uint64(expected)))
+    }
+
+    // 7 is a primitive root mod 2^31-1
+    test(2, 7, 1<<31-1)
+    test(123456789, 7, 1<<31-1)
+    test(123456789, 49, 1<<31-1)
+    // 2147483579 is a safe prime, 4 generates the subgroup of prime order 1073741789, 2 is not contained in it
+    test(modular.Pow32(4, 987654321, 2147483579), 4, 2147483579)
+    test(2, 4, 2147483579)
+  })
+
+  It("should calculate dlog for 40-bit primes", func() {
+    test := func(b, e, p uint64) {
+      x := new(big.Int).Exp(new(big.Int).SetUint64(b), new(big.Int).SetUint64(e), new(big.Int).SetUint64(p)).Uint64()
+
+      s, ok := modular.DLogIndexCalculus64(x, b, p)
+      ExpectWithOffset(1, ok).To(BeTrue())
+      ExpectWithOffset(1, s).To(Equal(e))
+    }
+
+    // 2^40 - 87 is prime and 13 is a primitive root
+    test(13, 987654321123, 1<<40-87)
+    test(13, 1<<40-89, 1<<40-87)
+    test(2, 123456789, 1<<40-87)
+  })
+})
@@ -0,0 +1,130 @@
+package modular
+
+import (
+  "fmt"
+  "strings"
+
+  "github.com/timebertt/grypto/euclid"
+)
+
+// DLogPohligHellman32 calculates the discrete logarithm of x to the given base and modulus using the Pohlig-Hellman
+// algorithm for int32 numbers. It returns the same results as DLog32 (the smallest y, so that b^y ≡ x mod m).
+// base must be coprime to the modulus, otherwise DLogPohligHellman32 panics.
+// The algorithm reduces the problem in the group generated by b of order n = p₁^e₁ * ... * pₖ^eₖ to one problem in
+// each subgroup of prime power order pᵢ^eᵢ, which in turn is reduced to eᵢ problems in the subgroup of prime order pᵢ.
+// These small problems are solved using the baby-step giant-step algorithm and the results are combined using the
+// chinese remainder theorem. Hence, the runtime depends on the largest prime factor of n instead of n itself:
+// if all prime factors of n are small (n is smooth), dlog is easy. This is why groups used in cryptography
+// (e.g. for Diffie-Hellman) need to have an order with at least one large prime factor.
+// See https://en.wikipedia.org/wiki/Pohlig%E2%80%93Hellman_algorithm.
+func DLogPohligHellman32(x, base, mod int32) (dlog int32, exists bool) {
+  return DLogPohligHellmanTrace32(x, base, mod, nil)
+}
+
+// DLogPohligHellmanTrace32 is like DLogPohligHellman32 but calls trace for each subproblem.
+func DLogPohligHellmanTrace32(x, base, mod int32, trace TraceFunc) (dlog int32, exists bool) {
+  if x <= 0 {
+    panic("grypto/modular: x must be greater than 0")
+  }
+  if base <= 0 {
+    panic("grypto/modular: base must be greater than 0")
+  }
+  if mod <= 0 {
+    panic("grypto/modular: modulus must be greater than 0")
+  }
+
+  if x == 1 {
+    return 0, true
+  }
+  if x >= mod {
+    return 0, false
+  }
+
+  base %= mod
+  if euclid.GreatestCommonDivisor(int(base), int(mod)) != 1 {
+    panic("grypto/modular: base must be coprime to the modulus")
+  }
+
+  n := orderOfUnit(base, mod)
+  factors := Factorize(uint64(n))
+  trace.printf("order(%d) mod %d = %d = %s", base, mod, n, formatFactors(factors))
+
+  if Pow32(x, n, mod) != 1 {
+    trace.printf("%d^%d ≢ 1 mod %d, so %d is not contained in the subgroup generated by %d", x, n, mod, x, base)
+    return 0, false
+  }
+
+  congruences := make([]Congruence, len(factors))
+
+  for i, f := range factors {
+    q := int32(f.Value())
+    // gᵢ = b^(n/q) and hᵢ = x^(n/q) are contained in the subgroup of order q
+    g, h := Pow32(base, n/q, mod), Pow32(x, n/q, mod)
+    trace.printf("subgroup of order %d: solve %d^y ≡ %d mod %d", q, g, h, mod)
+
+    y, ok := dlogPrimePower(h, g, mod, int32(f.Prime), f.Exponent, q, trace)
+    if !ok {
+      trace.printf("  dlog(%d) to the base %d mod %d does not exist", h, g, mod)
+      return 0, false
+    }
+    trace.printf("  => y ≡ %d mod %d", y, q)
+
+    congruences[i] = Congruence{Residue: int64(y), Modulus: int64(q)}
+  }
+
+  // the moduli are pairwise coprime, so the system always has a solution
+  solution, _ := CRT(congruences)
+  dlog = int32(solution.Residue)
+  trace.printf("chinese remainder theorem: y ≡ %d mod %d", dlog, n)
+
+  return dlog, true
+}
+
+// dlogPrimePower solves g^y ≡ h mod m in the subgroup of order q = p^e generated by g. It determines the digits of
+// y = y₀ + y₁*p + ... + yₑ₋₁*p^(e-1) one by one by solving dlog in the subgroup of order p generated by
+// γ = g^(p^(e-1)):
+//   γ^yₖ ≡ (g^-(y₀ + ... + yₖ₋₁*p^(k-1)) * h)^(p^(e-1-k)) mod m
+func dlogPrimePower(h, g, m, p int32, e int, q int32, trace TraceFunc) (y int32, exists bool) {
+  var (
+    // pk = p^k, pe = p^(e-1-k)
+    pk = int32(1)
+    pe = q / p
+  )
+
+  gamma := Pow32(g, pe, m)
+
+  for k := 0; k < e; k++ {
+    // g^-y = g^(q-y), as the order of g is q
+    hk := Pow32(int32(int64(Pow32(g, q-y, m))*int64(h)%int64(m)), pe, m)
+
+    d, ok := babyStepGiantStep(int64(hk), int64(gamma), 1, int64(m), int64(p))
+    if !ok {
+      return 0, false
+    }
+    trace.printf("  digit y%d: solve %d^y%d ≡ %d mod %d => y%d = %d", k, gamma, k, hk, m, k, d)
+
+    y += int32(d) * pk
+    if k < e-1 {
+      pk *= p
+      pe /= p
+    }
+  }
+
+  return y, true
+}
+
+func formatFactors(factors []PrimePower) string {
+  if len(factors) == 0 {
+    return "1"
+  }
+
+  parts := make([]string, len(factors))
+  for i, f := range factors {
+    if f.Exponent == 1 {
+      parts[i] = fmt.Sprint(f.Prime)
+    } else {
+      parts[i] = fmt.Sprintf("%d^%d", f.Prime, f.Exponent)
+    }
+  }
+  return strings.Join(parts, " * ")
+}
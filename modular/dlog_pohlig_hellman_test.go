@@ -0,0 +1,117 @@
+package modular_test
+
+import (
+  "fmt"
+  "math"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/euclid"
+  "github.com/timebertt/grypto/modular"
+)
+
+var _ = Describe("DLogPohligHellman32", func() {
+  It("should panic on invalid inputs", func() {
+    test := func(x, b, m int32) {
+      ExpectWithOffset(1, func() {
+        modular.DLogPohligHellman32(x, b, m)
+      }).To(Panic())
+    }
+
+    test(0, 1, 2)
+    test(-1, 1, 2)
+    test(2, -1, 3)
+    test(2, 1, -1)
+    test(2, 0, 3)
+    test(2, 1, 0)
+    // base not coprime to modulus
+    test(2, 2, 8)
+  })
+
+  It("should correctly calculate dlog", func() {
+    test := func(x, b, m, expected int32, expectedExists bool) {
+      s, ok := modular.DLogPohligHellman32(x, b, m)
+      ExpectWithOffset(1, ok).To(Equal(expectedExists))
+      if expectedExists {
+        ExpectWithOffset(1, s).To(Equal(expected))
+      }
+    }
+
+    test(1, 1, 13, 0, true)
+    test(1, 2, 13, 0, true)
+    test(3, 2, 13, 4, true)
+    test(5, 2, 13, 9, true)
+    test(9, 2, 13, 8, true)
+    test(12, 2, 13, 6, true)
+    test(11, 2, 13, 7, true)
+
+    test(2, 1, 13, 0, false)
+    test(3, 3, 8, 1, true)
+    test(5, 3, 8, 0, false)
+    test(13, 2, 13, 0, false)
+  })
+
+  It("should return the same results as DLog32", func() {
+    test := func(m, bStep int32) {
+      for b := int32(1); b <= m; b += bStep {
+        if euclid.GreatestCommonDivisor(int(b), int(m)) != 1 {
+          continue
+        }
+        for x := int32(1); x <= m; x++ {
+          expected, expectedExists := modular.DLog32(x, b, m)
+          s, ok := modular.DLogPohligHellman32(x, b, m)
+          ExpectWithOffset(1, ok).To(Equal(expectedExists), "dlog(%d) to the base %d mod %d", x, b, m)
+          ExpectWithOffset(1, s).To(Equal(expected), "dlog(%d) to the base %d mod %d", x, b, m)
+        }
+      }
+    }
+
+    for m := int32(1); m <= 64; m++ {
+      test(m, 1)
+    }
+    // moduli with larger groups, test only some of the bases for speed
+    test(509, 7)
+    test(561, 7)
+  })
+
+  It("should calculate dlog for large moduli with smooth group order", func() {
+    test := func(x, b, m int32) {
+      s, ok := modular.DLogPohligHellman32(x, b, m)
+      ExpectWithOffset(1, ok).To(BeTrue())
+      ExpectWithOffset(1, modular.Pow32(b, s, m)).To(Equal(x))
+
+      expected, _ := modular.DLogBabyStepGiantStep32(x, b, m)
+      ExpectWithOffset(1, s).To(Equal(expected))
+    }
+
+    // 7 is a primitive root mod 2^31-1, 2^31-2 = 2 * 3^2 * 7 * 11 * 31 * 151 * 331
+    test(2, 7, math.MaxInt32)
+    test(math.MaxInt32-1, 7, math.MaxInt32)
+    test(123456789, 7, math.MaxInt32)
+    // 2^30 is a power of 2 and therefore the order of 3 mod 2^30 is a power of 2 as well
+    test(modular.Pow32(3, 12345678, 1<<30), 3, 1<<30)
+  })
+
+  It("should trace all subproblems", func() {
+    var lines []string
+    trace := func(format string, a ...interface{}) {
+      lines = append(lines, fmt.Sprintf(format, a...))
+    }
+
+    s, ok := modular.DLogPohligHellmanTrace32(11, 2, 13, trace)
+    Expect(ok).To(BeTrue())
+    Expect(s).To(Equal(int32(7)))
+    Expect(lines).To(Equal([]string{
+      "order(2) mod 13 = 12 = 2^2 * 3",
+      "subgroup of order 4: solve 8^y ≡ 5 mod 13",
+      "  digit y0: solve 12^y0 ≡ 12 mod 13 => y0 = 1",
+      "  digit y1: solve 12^y1 ≡ 12 mod 13 => y1 = 1",
+      "  => y ≡ 3 mod 4",
+      "subgroup of order 3: solve 3^y ≡ 3 mod 13",
+      "  digit y0: solve 3^y0 ≡ 3 mod 13 => y0 = 1",
+      "  => y ≡ 1 mod 3",
+      "chinese remainder theorem: y ≡ 7 mod 12",
+    }))
+  })
+})
@@ -0,0 +1,164 @@
+package modular
+
+import (
+  "math/rand"
+
+  "github.com/timebertt/grypto/euclid"
+)
+
+const (
+  // pollardRhoMinOrder is the minimum order of the base, for which DLogPollardRho32 uses Pollard's rho algorithm.
+  // For smaller groups, enumeration is faster and doesn't suffer from degenerate cycles.
+  pollardRhoMinOrder = 16
+  // pollardRhoMaxCandidates is the maximum number of solutions of a collision equation, that are verified.
+  // If there are more solutions, the walk is restarted to find a better collision.
+  pollardRhoMaxCandidates = 1 << 16
+)
+
+// DLogPollardRho32 calculates the discrete logarithm of x to the given base and modulus using Pollard's rho algorithm
+// for logarithms for int32 numbers. It returns the same results as DLog32 (the smallest y, so that b^y ≡ x mod m),
+// but needs only constant memory and O(√n) steps, where n is the order of base.
+// base must be coprime to the modulus, otherwise DLogPollardRho32 panics.
+// The algorithm performs a pseudo-random walk through the group, where each element is known as b^a*x^c. The walk
+// partitions the group into three sets and either squares the current element or multiplies it by one of two fixed
+// elements depending on the set. As the group is finite, the walk eventually runs into a cycle, which is detected
+// using Floyd's algorithm (the walk looks like the greek letter ρ). A collision b^a₁*x^c₁ ≡ b^a₂*x^c₂ yields the equation
+//   (c₁ - c₂) * y ≡ a₂ - a₁ mod n
+// which can have several solutions. All of them are verified using Pow32.
+// See https://en.wikipedia.org/wiki/Pollard%27s_rho_algorithm_for_logarithms.
+func DLogPollardRho32(x, base, mod int32) (dlog int32, exists bool) {
+  if x <= 0 {
+    panic("grypto/modular: x must be greater than 0")
+  }
+  if base <= 0 {
+    panic("grypto/modular: base must be greater than 0")
+  }
+  if mod <= 0 {
+    panic("grypto/modular: modulus must be greater than 0")
+  }
+
+  if x == 1 {
+    return 0, true
+  }
+  if x >= mod {
+    return 0, false
+  }
+
+  base %= mod
+  if euclid.GreatestCommonDivisor(int(base), int(mod)) != 1 {
+    panic("grypto/modular: base must be coprime to the modulus")
+  }
+
+  n := orderOfUnit(base, mod)
+  if Pow32(x, n, mod) != 1 {
+    // x is not contained in the subgroup generated by base
+    return 0, false
+  }
+
+  if n < pollardRhoMinOrder {
+    exp := int32(1)
+    for i := int32(0); i < n; i++ {
+      if exp == x {
+        return i, true
+      }
+      exp = int32(int64(exp) * int64(base) % int64(mod))
+    }
+    return 0, false
+  }
+
+  // use a fixed seed, so that results are reproducible
+  rnd := rand.New(rand.NewSource(1))
+
+  for {
+    w := newRhoWalk(int64(base), int64(x), int64(mod), int64(n), rnd)
+    tortoise := w.element(rnd.Int63n(w.n), rnd.Int63n(w.n))
+    hare := tortoise
+
+    for {
+      tortoise = w.step(tortoise)
+      hare = w.step(w.step(hare))
+      if tortoise.v == hare.v {
+        break
+      }
+    }
+
+    dlog, exists, ok := w.solve(tortoise, hare)
+    if ok {
+      return dlog, exists
+    }
+    // degenerate collision, restart with a different walk
+  }
+}
+
+// rhoWalk holds the parameters of the pseudo-random walk of DLogPollardRho32.
+type rhoWalk struct {
+  b, x, m int64
+  // n is the order of b
+  n int64
+  // multipliers are the random elements, that the walk multiplies by in the second and third set
+  multipliers [2]rhoState
+}
+
+// rhoState is an element v ≡ b^a * x^c mod m of the walk.
+type rhoState struct {
+  v, a, c int64
+}
+
+func newRhoWalk(b, x, m, n int64, rnd *rand.Rand) *rhoWalk {
+  w := &rhoWalk{b: b, x: x, m: m, n: n}
+  // in the textbook version, the multipliers are simply b and x. Choosing them randomly ensures, that the walk
+  // differs on every restart.
+  for i := range w.multipliers {
+    w.multipliers[i] = w.element(rnd.Int63n(n), rnd.Int63n(n))
+  }
+  return w
+}
+
+func (w *rhoWalk) element(a, c int64) rhoState {
+  v := int64(Pow32(int32(w.b), int32(a), int32(w.m))) * int64(Pow32(int32(w.x), int32(c), int32(w.m))) % w.m
+  return rhoState{v: v, a: a, c: c}
+}
+
+func (w *rhoWalk) step(s rhoState) rhoState {
+  i := s.v % 3
+  if i == 0 {
+    return rhoState{v: s.v * s.v % w.m, a: 2 * s.a % w.n, c: 2 * s.c % w.n}
+  }
+
+  f := w.multipliers[i-1]
+  return rhoState{v: s.v * f.v % w.m, a: (s.a + f.a) % w.n, c: (s.c + f.c) % w.n}
+}
+
+// solve solves the collision equation (c₁ - c₂) * y ≡ a₂ - a₁ mod n and verifies all solutions.
+// It returns ok=false, if the collision is degenerate and the walk needs to be restarted.
+func (w *rhoWalk) solve(s1, s2 rhoState) (dlog int32, exists, ok bool) {
+  d := ((s1.c-s2.c)%w.n + w.n) % w.n
+  r := ((s2.a-s1.a)%w.n + w.n) % w.n
+  if d == 0 {
+    return 0, false, false
+  }
+
+  // d*y ≡ r mod n is solvable iff g = gcd(d, n) divides r, then it has exactly g solutions modulo n
+  g := int64(euclid.GreatestCommonDivisor(int(d), int(w.n)))
+  if r%g != 0 {
+    // if x was contained in ⟨b⟩, the equation would be solvable
+    return 0, false, true
+  }
+  if g > pollardRhoMaxCandidates {
+    return 0, false, false
+  }
+
+  // y₀ ≡ r/g * (d/g)⁻¹ mod n/g, all solutions are y₀ + k*n/g for k=0,...,g-1
+  n0 := w.n / g
+  inv, _ := Inverse64(d/g, n0)
+  y0 := r / g % n0 * inv % n0
+
+  for k := int64(0); k < g; k++ {
+    if y := y0 + k*n0; int64(Pow32(int32(w.b), int32(y), int32(w.m))) == w.x {
+      return int32(y), true, true
+    }
+  }
+
+  // the logarithm of x would be one of the solutions, if it existed
+  return 0, false, true
+}
@@ -0,0 +1,101 @@
+package modular_test
+
+import (
+  "math"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/euclid"
+  "github.com/timebertt/grypto/modular"
+)
+
+var _ = Describe("DLogPollardRho32", func() {
+  It("should panic on invalid inputs", func() {
+    test := func(x, b, m int32) {
+      ExpectWithOffset(1, func() {
+        modular.DLogPollardRho32(x, b, m)
+      }).To(Panic())
+    }
+
+    test(0, 1, 2)
+    test(-1, 1, 2)
+    test(2, -1, 3)
+    test(2, 1, -1)
+    test(2, 0, 3)
+    test(2, 1, 0)
+    // base not coprime to modulus
+    test(2, 2, 8)
+  })
+
+  It("should correctly calculate dlog", func() {
+    test := func(x, b, m, expected int32, expectedExists bool) {
+      s, ok := modular.DLogPollardRho32(x, b, m)
+      ExpectWithOffset(1, ok).To(Equal(expectedExists))
+      if expectedExists {
+        ExpectWithOffset(1, s).To(Equal(expected))
+      }
+    }
+
+    test(1, 1, 13, 0, true)
+    test(1, 2, 13, 0, true)
+    test(3, 2, 13, 4, true)
+    test(5, 2, 13, 9, true)
+    test(9, 2, 13, 8, true)
+    test(12, 2, 13, 6, true)
+    test(11, 2, 13, 7, true)
+
+    test(2, 1, 13, 0, false)
+    test(3, 3, 8, 1, true)
+    test(5, 3, 8, 0, false)
+    test(13, 2, 13, 0, false)
+  })
+
+  It("should return the same results as DLog32", func() {
+    test := func(m, bStep int32) {
+      for b := int32(1); b <= m; b += bStep {
+        if euclid.GreatestCommonDivisor(int(b), int(m)) != 1 {
+          continue
+        }
+        for x := int32(1); x <= m; x++ {
+          expected, expectedExists := modular.DLog32(x, b, m)
+          s, ok := modular.DLogPollardRho32(x, b, m)
+          ExpectWithOffset(1, ok).To(Equal(expectedExists), "dlog(%d) to the base %d mod %d", x, b, m)
+          ExpectWithOffset(1, s).To(Equal(expected), "dlog(%d) to the base %d mod %d", x, b, m)
+        }
+      }
+    }
+
+    for m := int32(1); m <= 64; m++ {
+      test(m, 1)
+    }
+    // moduli with larger groups, test only some of the bases for speed
+    test(509, 7)
+    test(561, 7)
+  })
+
+  It("should calculate dlog for large moduli", func() {
+    test := func(x, b, m int32) {
+      s, ok := modular.DLogPollardRho32(x, b, m)
+      ExpectWithOffset(1, ok).To(BeTrue())
+      ExpectWithOffset(1, modular.Pow32(b, s, m)).To(Equal(x))
+
+      expected, _ := modular.DLogBabyStepGiantStep32(x, b, m)
+      ExpectWithOffset(1, s).To(Equal(expected))
+    }
+
+    // 7 is a primitive root mod 2^31-1
+    test(2, 7, math.MaxInt32)
+    test(math.MaxInt32-1, 7, math.MaxInt32)
+    test(123456789, 7, math.MaxInt32)
+    // 2147483579 = 2*1073741789+1 is a safe prime, 4 generates the subgroup of prime order 1073741789
+    test(modular.Pow32(4, 987654321, 2147483579), 4, 2147483579)
+    test(modular.Pow32(4, 1073741788, 2147483579), 4, 2147483579)
+  })
+
+  It("should detect that dlog does not exist in large groups", func() {
+    // 2 is a quadratic non-residue mod 2147483579 and therefore not contained in the subgroup generated by 4
+    _, ok := modular.DLogPollardRho32(2, 4, 2147483579)
+    Expect(ok).To(BeFalse())
+  })
+})
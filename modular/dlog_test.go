@@ -2,6 +2,7 @@ package modular_test
 
 import (
   "math"
+  "math/big"
 
   . "github.com/onsi/ginkgo"
   . "github.com/onsi/gomega"
@@ -47,3 +48,43 @@ var _ = Describe("DLog32", func() {
     test(3, 2, 8, 0, false)
   })
 })
+
+var _ = Describe("DLogBig", func() {
+  It("should panic on invalid inputs", func() {
+    test := func(x, b, m int64) {
+      ExpectWithOffset(1, func() {
+        modular.DLogBig(big.NewInt(x), big.NewInt(b), big.NewInt(m))
+      }).To(Panic())
+    }
+
+    test(0, 1, 2)
+    test(-1, 1, 2)
+    test(1, -1, 2)
+    test(1, 1, -1)
+    test(1, 0, 2)
+    test(1, 1, 0)
+    test(1, 2, math.MaxInt32)
+  })
+
+  It("should return the same results as DLog32", func() {
+    for m := int32(1); m <= 40; m++ {
+      for b := int32(1); b <= m; b++ {
+        for x := int32(1); x <= m; x++ {
+          expected, expectedExists := modular.DLog32(x, b, m)
+          s, ok := modular.DLogBig(big.NewInt(int64(x)), big.NewInt(int64(b)), big.NewInt(int64(m)))
+          Expect(ok).To(Equal(expectedExists), "dlog(%d) to the base %d mod %d", x, b, m)
+          if expectedExists {
+            Expect(s.Int64()).To(Equal(int64(expected)), "dlog(%d) to the base %d mod %d", x, b, m)
+          }
+        }
+      }
+    }
+  })
+
+  It("should correctly calculate dlog for large bases", func() {
+    b, _ := new(big.Int).SetString("100000000000000000002", 10) // ≡ 2 mod 13
+    s, ok := modular.DLogBig(big.NewInt(3), b, big.NewInt(13))
+    Expect(ok).To(BeTrue())
+    Expect(s).To(Equal(big.NewInt(4)))
+  })
+})
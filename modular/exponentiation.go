@@ -1,5 +1,7 @@
 package modular
 
+import "math/big"
+
 // Pow32 implements modular exponentiation using the square-and-multiply method for int32 numbers.
 // It returns a value x so that x = base ^ exp mod m.
 // Modular exponentiation is heavily used e.g. for primality tests and public-key cryptography (like RSA).
@@ -54,3 +56,40 @@ func Pow32(base, exp, mod int32) int32 {
   // result will never outgrow modulus => x < mod <= MaxInt32
   return int32(x)
 }
+
+// PowBig is like Pow32 but for arbitrarily large integers.
+func PowBig(base, exp, mod *big.Int) *big.Int {
+  if mod.Sign() <= 0 {
+    panic("grypto/modular: modulus must be greater than 0")
+  }
+  if exp.Sign() < 0 {
+    panic("grypto/modular: negative exponent not allowed")
+  }
+  if base.Sign() == 0 && exp.Sign() == 0 {
+    panic("grypto/modular: 0^0 is not defined")
+  }
+
+  // special cases
+  if base.Sign() == 0 {
+    return new(big.Int)
+  }
+  if exp.Sign() == 0 {
+    return big.NewInt(1)
+  }
+
+  // square-and-multiply (fast exponentiation), Mod also normalizes negative bases
+  var (
+    x = big.NewInt(1)
+    a = new(big.Int).Mod(base, mod)
+  )
+
+  for i := 0; i < exp.BitLen(); i++ {
+    if exp.Bit(i) == 1 {
+      x.Mul(x, a).Mod(x, mod) // multiply
+    }
+
+    a.Mul(a, a).Mod(a, mod) // square
+  }
+
+  return x
+}
@@ -2,6 +2,7 @@ package modular_test
 
 import (
   "math"
+  "math/big"
   "testing"
 
   . "github.com/onsi/ginkgo"
@@ -50,6 +51,52 @@ var _ = Describe("Pow32", func() {
   })
 })
 
+var _ = Describe("PowBig", func() {
+  It("should panic on invalid inputs", func() {
+    test := func(b, e, m int64) {
+      ExpectWithOffset(1, func() {
+        modular.PowBig(big.NewInt(b), big.NewInt(e), big.NewInt(m))
+      }).To(Panic())
+    }
+
+    test(1, 2, -1)
+    test(1, 2, 0)
+    test(1, -1, 1)
+    test(0, 0, 1)
+  })
+
+  It("should return the same results as Pow32", func() {
+    for m := int32(1); m <= 30; m++ {
+      for b := -m; b <= m; b++ {
+        for e := int32(0); e <= 40; e++ {
+          if b == 0 && e == 0 {
+            continue
+          }
+          pow := modular.PowBig(big.NewInt(int64(b)), big.NewInt(int64(e)), big.NewInt(int64(m)))
+          Expect(pow.Int64()).To(Equal(int64(modular.Pow32(b, e, m))), "%d ^ %d mod %d", b, e, m)
+        }
+      }
+    }
+
+    test := func(b, e, m int32) {
+      pow := modular.PowBig(big.NewInt(int64(b)), big.NewInt(int64(e)), big.NewInt(int64(m)))
+      ExpectWithOffset(1, pow.Int64()).To(Equal(int64(modular.Pow32(b, e, m))))
+    }
+    test(math.MaxInt32-3, math.MaxInt32, math.MaxInt32-1)
+    test(-2, 35, 561)
+  })
+
+  It("should correctly calculate modular exponentiation of large integers", func() {
+    m, _ := new(big.Int).SetString("170141183460469231731687303715884105727", 10) // 2^127-1
+    b, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+    e, _ := new(big.Int).SetString("98765432109876543210987654321", 10)
+
+    Expect(modular.PowBig(b, e, m)).To(Equal(new(big.Int).Exp(b, e, m)))
+    // Fermat's little theorem
+    Expect(modular.PowBig(b, new(big.Int).Sub(m, big.NewInt(1)), m)).To(Equal(big.NewInt(1)))
+  })
+})
+
 var result int32
 
 func BenchmarkPow32MaxInt32(b *testing.B) {
@@ -59,3 +106,18 @@ func BenchmarkPow32MaxInt32(b *testing.B) {
   }
   result = r
 }
+
+var resultBig *big.Int
+
+func BenchmarkPowBigMaxInt32(b *testing.B) {
+  var (
+    r    *big.Int
+    base = big.NewInt(math.MaxInt32 - 3)
+    exp  = big.NewInt(math.MaxInt32)
+    mod  = big.NewInt(math.MaxInt32 - 1)
+  )
+  for n := 0; n < b.N; n++ {
+    r = modular.PowBig(base, exp, mod)
+  }
+  resultBig = r
+}
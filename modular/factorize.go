@@ -0,0 +1,70 @@
+package modular
+
+// PrimePower is a single factor p^e of a prime factorization.
+type PrimePower struct {
+  Prime    uint64
+  Exponent int
+}
+
+// Value returns p^e.
+func (p PrimePower) Value() uint64 {
+  v := uint64(1)
+  for i := 0; i < p.Exponent; i++ {
+    v *= p.Prime
+  }
+  return v
+}
+
+// Factorize calculates the prime factorization of n using trial division, i.e. the primes p₁ < p₂ < ... < pₖ and
+// exponents e₁, e₂, ..., eₖ so that n = p₁^e₁ * p₂^e₂ * ... * pₖ^eₖ. The factors are returned in ascending order.
+// For n <= 1, the factorization is empty.
+// Trial division needs up to √n steps, so it is only practicable for small numbers (or numbers with small factors).
+// See https://en.wikipedia.org/wiki/Integer_factorization.
+func Factorize(n uint64) []PrimePower {
+  var factors []PrimePower
+
+  // p <= n/p avoids overflows of p*p
+  for p := uint64(2); p <= n/p; p++ {
+    if n%p != 0 {
+      continue
+    }
+
+    factor := PrimePower{Prime: p}
+    for n%p == 0 {
+      n /= p
+      factor.Exponent++
+    }
+    factors = append(factors, factor)
+  }
+
+  if n > 1 {
+    factors = append(factors, PrimePower{Prime: n, Exponent: 1})
+  }
+
+  return factors
+}
+
+// orderOfUnit calculates the order of a unit b modulo m. In contrast to OrderOf, it doesn't enumerate all powers of b
+// but uses that the order divides φ(m) (Lagrange's theorem): starting with φ(m), it divides out the prime factors as
+// long as b^(n/p) ≡ 1 mod m.
+func orderOfUnit(b, m int32) int32 {
+  return int32(orderOfUnit64(uint64(b), uint64(m)))
+}
+
+// orderOfUnit64 is like orderOfUnit but for uint64 numbers.
+func orderOfUnit64(b, m uint64) uint64 {
+  // φ(m) = m * Π (1 - 1/p) for all primes p dividing m
+  phi := m
+  for _, f := range Factorize(m) {
+    phi = phi / f.Prime * (f.Prime - 1)
+  }
+
+  order := phi
+  for _, f := range Factorize(phi) {
+    for i := 0; i < f.Exponent && Pow64(b, order/f.Prime, m) == 1%m; i++ {
+      order /= f.Prime
+    }
+  }
+
+  return order
+}
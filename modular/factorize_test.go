@@ -0,0 +1,40 @@
+package modular_test
+
+import (
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/modular"
+)
+
+var _ = Describe("Factorize", func() {
+  It("should correctly factorize numbers", func() {
+    test := func(n uint64, expected ...modular.PrimePower) {
+      ExpectWithOffset(1, modular.Factorize(n)).To(Equal(expected))
+    }
+
+    test(0)
+    test(1)
+    test(2, modular.PrimePower{Prime: 2, Exponent: 1})
+    test(12, modular.PrimePower{Prime: 2, Exponent: 2}, modular.PrimePower{Prime: 3, Exponent: 1})
+    test(1024, modular.PrimePower{Prime: 2, Exponent: 10})
+    test(2147483646,
+      modular.PrimePower{Prime: 2, Exponent: 1}, modular.PrimePower{Prime: 3, Exponent: 2},
+      modular.PrimePower{Prime: 7, Exponent: 1}, modular.PrimePower{Prime: 11, Exponent: 1},
+      modular.PrimePower{Prime: 31, Exponent: 1}, modular.PrimePower{Prime: 151, Exponent: 1},
+      modular.PrimePower{Prime: 331, Exponent: 1},
+    )
+    test(2147483647, modular.PrimePower{Prime: 2147483647, Exponent: 1})
+    test(1<<63+1<<62, modular.PrimePower{Prime: 2, Exponent: 62}, modular.PrimePower{Prime: 3, Exponent: 1})
+  })
+
+  It("should multiply the factors to the original number", func() {
+    for n := uint64(1); n <= 10000; n++ {
+      product := uint64(1)
+      for _, f := range modular.Factorize(n) {
+        product *= f.Value()
+      }
+      Expect(product).To(Equal(n))
+    }
+  })
+})
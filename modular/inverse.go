@@ -0,0 +1,74 @@
+package modular
+
+import (
+  "fmt"
+  "math/big"
+
+  "github.com/timebertt/grypto/euclid"
+)
+
+// ErrNotInvertible is returned by the Inverse functions, if a is not invertible modulo m, i.e. if a and m are not
+// coprime.
+type ErrNotInvertible struct {
+  // A is the number as given by the caller, i.e. before normalizing it modulo m.
+  A, Modulus *big.Int
+  // GCD is the greatest common divisor of a and m, which is greater than 1.
+  GCD *big.Int
+}
+
+func (e *ErrNotInvertible) Error() string {
+  return fmt.Sprintf("%s is not invertible mod %s: gcd(%s, %s) = %s", e.A, e.Modulus, e.A, e.Modulus, e.GCD)
+}
+
+// Inverse32 calculates the multiplicative inverse of a modulo m for int32 numbers, i.e. the number a⁻¹ in the range
+// [0, m), so that a*a⁻¹ ≡ 1 mod m. Negative numbers are normalized first.
+// a is invertible modulo m if and only if gcd(a, m) = 1. In this case, the extended euclidean algorithm yields
+// x and y, so that x*a + y*m = 1, i.e. x*a ≡ 1 mod m. Otherwise, Inverse32 returns an *ErrNotInvertible.
+// m must be greater than 0, otherwise Inverse32 panics.
+func Inverse32(a, m int32) (int32, error) {
+  inv, err := Inverse64(int64(a), int64(m))
+  return int32(inv), err
+}
+
+// Inverse64 is like Inverse32 but for int64 numbers.
+func Inverse64(a, m int64) (int64, error) {
+  if m <= 0 {
+    panic("grypto/modular: modulus must be greater than 0")
+  }
+
+  // normalize a, but keep the original value for the error
+  r := a % m
+  if r < 0 {
+    r += m
+  }
+
+  gcd, x, _ := euclid.GreatestCommonDivisorExtended(int(r), int(m))
+  if gcd != 1 {
+    return 0, &ErrNotInvertible{A: big.NewInt(a), Modulus: big.NewInt(m), GCD: big.NewInt(int64(gcd))}
+  }
+
+  // normalize inverse, x might be negative
+  inv := int64(x) % m
+  if inv < 0 {
+    inv += m
+  }
+  return inv, nil
+}
+
+// InverseBig is like Inverse32 but for arbitrarily large integers.
+func InverseBig(a, m *big.Int) (*big.Int, error) {
+  if m.Sign() <= 0 {
+    panic("grypto/modular: modulus must be greater than 0")
+  }
+
+  // normalize a, but keep the original value for the error
+  r := new(big.Int).Mod(a, m)
+
+  gcd, x, _ := euclid.GreatestCommonDivisorExtendedBig(r, m)
+  if gcd.Cmp(big.NewInt(1)) != 0 {
+    return nil, &ErrNotInvertible{A: new(big.Int).Set(a), Modulus: new(big.Int).Set(m), GCD: gcd}
+  }
+
+  // normalize inverse, x might be negative
+  return x.Mod(x, m), nil
+}
@@ -0,0 +1,120 @@
+package modular_test
+
+import (
+  "math"
+  "math/big"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/modular"
+)
+
+var _ = Describe("Inverse32", func() {
+  It("should panic on invalid moduli", func() {
+    Expect(func() { _, _ = modular.Inverse32(1, 0) }).To(Panic())
+    Expect(func() { _, _ = modular.Inverse32(1, -7) }).To(Panic())
+  })
+
+  It("should correctly calculate inverses", func() {
+    test := func(a, m, expected int32) {
+      inv, err := modular.Inverse32(a, m)
+      ExpectWithOffset(1, err).NotTo(HaveOccurred())
+      ExpectWithOffset(1, inv).To(Equal(expected))
+    }
+
+    test(3, 7, 5)
+    test(-3, 7, 2)
+    test(10, 7, 5)
+    test(1, 1, 0)
+    test(0, 1, 0)
+    test(2, math.MaxInt32, 1<<30)
+    test(math.MaxInt32-1, math.MaxInt32, math.MaxInt32-1)
+  })
+
+  It("should verify all inverses", func() {
+    for m := int32(1); m <= 100; m++ {
+      for a := -m; a <= m; a++ {
+        inv, err := modular.Inverse32(a, m)
+        if err != nil {
+          Expect(err.(*modular.ErrNotInvertible).GCD.Int64()).To(BeNumerically(">", 1), "%d mod %d", a, m)
+          continue
+        }
+        Expect(inv).To(And(BeNumerically(">=", 0), BeNumerically("<", m)))
+        product := (int64(a)*int64(inv)%int64(m) + int64(m)) % int64(m)
+        Expect(product).To(Equal(int64(1%m)), "%d mod %d", a, m)
+      }
+    }
+  })
+
+  It("should return an error if a is not invertible", func() {
+    _, err := modular.Inverse32(4, 6)
+    Expect(err).To(Equal(&modular.ErrNotInvertible{A: big.NewInt(4), Modulus: big.NewInt(6), GCD: big.NewInt(2)}))
+    Expect(err).To(MatchError("4 is not invertible mod 6: gcd(4, 6) = 2"))
+
+    _, err = modular.Inverse32(0, 5)
+    Expect(err).To(MatchError("0 is not invertible mod 5: gcd(0, 5) = 5"))
+
+    // the error reports a as given, not the normalized value
+    _, err = modular.Inverse32(-4, 6)
+    Expect(err).To(MatchError("-4 is not invertible mod 6: gcd(-4, 6) = 2"))
+    _, err = modular.Inverse32(16, 6)
+    Expect(err).To(MatchError("16 is not invertible mod 6: gcd(16, 6) = 2"))
+  })
+})
+
+var _ = Describe("Inverse64", func() {
+  It("should correctly calculate inverses of large integers", func() {
+    test := func(a, m, expected int64) {
+      inv, err := modular.Inverse64(a, m)
+      ExpectWithOffset(1, err).NotTo(HaveOccurred())
+      ExpectWithOffset(1, inv).To(Equal(expected))
+    }
+
+    test(3, 7, 5)
+    test(-3, 7, 2)
+    test(2, math.MaxInt64, 1<<62)
+    test(math.MaxInt64-1, math.MaxInt64, math.MaxInt64-1)
+    test(math.MinInt64, math.MaxInt64, math.MaxInt64-1)
+  })
+
+  It("should return an error if a is not invertible", func() {
+    _, err := modular.Inverse64(1<<40, 1<<62)
+    Expect(err).To(Equal(&modular.ErrNotInvertible{A: big.NewInt(1 << 40), Modulus: big.NewInt(1 << 62),
+      GCD: big.NewInt(1 << 40)}))
+
+    _, err = modular.Inverse64(-4, 6)
+    Expect(err).To(MatchError("-4 is not invertible mod 6: gcd(-4, 6) = 2"))
+  })
+})
+
+var _ = Describe("InverseBig", func() {
+  It("should panic on invalid moduli", func() {
+    Expect(func() { _, _ = modular.InverseBig(big.NewInt(1), big.NewInt(0)) }).To(Panic())
+    Expect(func() { _, _ = modular.InverseBig(big.NewInt(1), big.NewInt(-7)) }).To(Panic())
+  })
+
+  It("should return the same results as Inverse32", func() {
+    for m := int32(1); m <= 50; m++ {
+      for a := -m; a <= m; a++ {
+        inv, err := modular.Inverse32(a, m)
+        invBig, errBig := modular.InverseBig(big.NewInt(int64(a)), big.NewInt(int64(m)))
+        if err != nil {
+          Expect(errBig).To(Equal(err), "%d mod %d", a, m)
+          continue
+        }
+        Expect(errBig).NotTo(HaveOccurred())
+        Expect(invBig.Int64()).To(Equal(int64(inv)), "%d mod %d", a, m)
+      }
+    }
+  })
+
+  It("should correctly calculate inverses of large integers", func() {
+    m, _ := new(big.Int).SetString("170141183460469231731687303715884105727", 10) // 2^127-1
+    a, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+    inv, err := modular.InverseBig(a, m)
+    Expect(err).NotTo(HaveOccurred())
+    Expect(inv).To(Equal(new(big.Int).ModInverse(a, m)))
+  })
+})
@@ -0,0 +1,61 @@
+package modular
+
+// Jacobi calculates the Jacobi symbol (a/n) for an odd positive integer n. It is a generalization of the Legendre
+// symbol: for n = p₁^e₁ * ... * pₖ^eₖ, (a/n) = (a/p₁)^e₁ * ... * (a/pₖ)^eₖ. The result is 0 if gcd(a, n) > 1,
+// otherwise it is 1 or -1.
+// If (a/n) = -1, a is definitely not a quadratic residue modulo n. However, if (a/n) = 1, a is not necessarily a
+// quadratic residue modulo n, unless n is prime.
+// Jacobi doesn't need to factorize n. Instead, it repeatedly applies the law of quadratic reciprocity
+// (a/n) = (n/a) * (-1)^((a-1)/2 * (n-1)/2) for odd a, the second supplementary law (2/n) = (-1)^((n²-1)/8) and
+// (a/n) = (a mod n/n), similar to the euclidean algorithm.
+// n must be an odd positive integer, otherwise Jacobi panics.
+// See https://en.wikipedia.org/wiki/Jacobi_symbol.
+func Jacobi(a, n int64) int {
+  if n <= 0 || n%2 == 0 {
+    panic("grypto/modular: n must be an odd positive integer")
+  }
+
+  // normalize a
+  a %= n
+  if a < 0 {
+    a += n
+  }
+
+  result := 1
+  for a != 0 {
+    // factor out powers of 2: (2/n) = -1 if n ≡ 3, 5 mod 8
+    for a%2 == 0 {
+      a /= 2
+      if r := n % 8; r == 3 || r == 5 {
+        result = -result
+      }
+    }
+
+    // quadratic reciprocity: (a/n) = -(n/a) if a ≡ n ≡ 3 mod 4
+    a, n = n, a
+    if a%4 == 3 && n%4 == 3 {
+      result = -result
+    }
+    a %= n
+  }
+
+  if n != 1 {
+    // gcd(a, n) = n > 1
+    return 0
+  }
+  return result
+}
+
+// Legendre calculates the Legendre symbol (a/p) for an odd prime p. The result is 0 if p divides a, 1 if a is a
+// quadratic residue modulo p and -1 if a is a quadratic nonresidue modulo p. According to Euler's criterion,
+// (a/p) ≡ a^((p-1)/2) mod p.
+// For primes, the Legendre symbol is equal to the Jacobi symbol, so Legendre uses Jacobi. It doesn't check whether p
+// is actually prime, but p must be an odd integer greater than 2, otherwise Legendre panics.
+// See https://en.wikipedia.org/wiki/Legendre_symbol.
+func Legendre(a, p int64) int {
+  if p <= 2 || p%2 == 0 {
+    panic("grypto/modular: p must be an odd prime")
+  }
+
+  return Jacobi(a, p)
+}
@@ -0,0 +1,88 @@
+package modular_test
+
+import (
+  "math"
+  "math/big"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/modular"
+)
+
+var _ = Describe("Jacobi", func() {
+  It("should panic on invalid n", func() {
+    Expect(func() { modular.Jacobi(1, 0) }).To(Panic())
+    Expect(func() { modular.Jacobi(1, -3) }).To(Panic())
+    Expect(func() { modular.Jacobi(1, 8) }).To(Panic())
+  })
+
+  It("should correctly calculate Jacobi symbols", func() {
+    test := func(a, n int64, expected int) {
+      ExpectWithOffset(1, modular.Jacobi(a, n)).To(Equal(expected), "(%d/%d)", a, n)
+    }
+
+    test(0, 1, 1)
+    test(5, 1, 1)
+    test(1, 3, 1)
+    test(2, 3, -1)
+    test(3, 3, 0)
+    test(2, 15, 1)
+    test(7, 15, -1)
+    test(5, 15, 0)
+    test(1001, 9907, -1)
+    test(19, 45, 1)
+    test(8, 21, -1)
+    test(5, 21, 1)
+    test(-1, 7, -1)
+    test(-1, 13, 1)
+    test(-2, 15, -1)
+    test(math.MaxInt64, 3, 1)
+  })
+
+  It("should return the same results as big.Jacobi", func() {
+    for n := int64(1); n < 300; n += 2 {
+      for a := int64(-300); a < 300; a++ {
+        Expect(modular.Jacobi(a, n)).To(Equal(big.Jacobi(big.NewInt(a), big.NewInt(n))), "(%d/%d)", a, n)
+      }
+    }
+
+    n := int64(math.MaxInt64)
+    for a := int64(1); a < 1000; a++ {
+      Expect(modular.Jacobi(a, n)).To(Equal(big.Jacobi(big.NewInt(a), big.NewInt(n))), "(%d/%d)", a, n)
+    }
+  })
+})
+
+var _ = Describe("Legendre", func() {
+  It("should panic on invalid p", func() {
+    Expect(func() { modular.Legendre(1, 2) }).To(Panic())
+    Expect(func() { modular.Legendre(1, 1) }).To(Panic())
+    Expect(func() { modular.Legendre(1, -7) }).To(Panic())
+  })
+
+  It("should be consistent with Euler's criterion", func() {
+    for _, p := range []int64{3, 5, 7, 11, 13, 101, 1009, 2147483647} {
+      for a := int64(0); a < 200; a++ {
+        expected := modular.Pow64(uint64(a), uint64(p-1)/2, uint64(p))
+        if expected == uint64(p-1) {
+          Expect(modular.Legendre(a, p)).To(Equal(-1), "(%d/%d)", a, p)
+        } else {
+          Expect(modular.Legendre(a, p)).To(Equal(int(expected)), "(%d/%d)", a, p)
+        }
+      }
+    }
+  })
+
+  It("should detect quadratic residues", func() {
+    // quadratic residues modulo 11 are 1, 3, 4, 5, 9
+    residues := map[int64]bool{1: true, 3: true, 4: true, 5: true, 9: true}
+    for a := int64(1); a < 11; a++ {
+      expected := -1
+      if residues[a] {
+        expected = 1
+      }
+      Expect(modular.Legendre(a, 11)).To(Equal(expected), "(%d/11)", a)
+    }
+  })
+})
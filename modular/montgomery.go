@@ -0,0 +1,291 @@
+package modular
+
+import (
+  "math/big"
+  "math/bits"
+)
+
+// Montgomery implements Montgomery multiplication and exponentiation for a fixed odd uint64 modulus m.
+// Pow32 and Pow64 reduce every intermediate product modulo m, which requires an expensive division. Montgomery
+// multiplication instead works on numbers in Montgomery form ã = a*R mod m with R = 2^64. The product of two numbers
+// in Montgomery form is reduced using REDC, which only needs multiplications, additions and shifts (division by R is
+// a shift, reduction modulo R is a truncation): REDC(ã*b̃) = a*b*R mod m is again in Montgomery form.
+// As converting to and from Montgomery form costs a few multiplications itself, Montgomery multiplication pays off
+// for long chains of multiplications with the same modulus, like in modular exponentiation.
+// R must be coprime to m, which is why Montgomery multiplication only works for odd moduli.
+// See https://en.wikipedia.org/wiki/Montgomery_modular_multiplication.
+type Montgomery struct {
+  // Trace is called with the intermediate values of Exp, if it is not nil.
+  Trace TraceFunc
+
+  mod uint64
+  // modInv is -m⁻¹ mod R
+  modInv uint64
+  // r1 is R mod m, i.e. 1 in Montgomery form
+  r1 uint64
+  // r2 is R² mod m, used for converting numbers to Montgomery form
+  r2 uint64
+}
+
+// NewMontgomery returns a new Montgomery context for the given modulus. mod must be odd and greater than 1,
+// otherwise NewMontgomery panics.
+func NewMontgomery(mod uint64) *Montgomery {
+  if mod%2 == 0 || mod == 1 {
+    panic("grypto/modular: modulus must be odd and greater than 1")
+  }
+
+  // calculate m⁻¹ mod R using Newton's method: if m*x ≡ 1 mod 2^k, then m*x*(2-m*x) ≡ 1 mod 2^2k.
+  // m*m ≡ 1 mod 8 for all odd m, so m is its own inverse modulo 2^3 and 5 iterations yield the inverse modulo 2^96.
+  inv := mod
+  for i := 0; i < 5; i++ {
+    inv *= 2 - mod*inv
+  }
+
+  // R mod m = (R - m) mod m, as R doesn't fit into an uint64
+  r1 := -mod % mod
+
+  return &Montgomery{
+    mod:    mod,
+    modInv: -inv,
+    r1:     r1,
+    r2:     MulMod64(r1, r1, mod),
+  }
+}
+
+// Modulus returns the modulus of the Montgomery context.
+func (mt *Montgomery) Modulus() uint64 {
+  return mt.mod
+}
+
+// ToMontgomery converts a to Montgomery form, i.e. it returns a*R mod m.
+func (mt *Montgomery) ToMontgomery(a uint64) uint64 {
+  // REDC(a*R²) = a*R mod m
+  return mt.REDC(bits.Mul64(a%mt.mod, mt.r2))
+}
+
+// FromMontgomery converts ã from Montgomery form back to the usual representation, i.e. it returns ã*R⁻¹ mod m.
+func (mt *Montgomery) FromMontgomery(a uint64) uint64 {
+  return mt.REDC(0, a)
+}
+
+// Convert is the same as ToMontgomery, it implements the Reducer interface.
+func (mt *Montgomery) Convert(a uint64) uint64 {
+  return mt.ToMontgomery(a)
+}
+
+// Revert is the same as FromMontgomery, it implements the Reducer interface.
+func (mt *Montgomery) Revert(a uint64) uint64 {
+  return mt.FromMontgomery(a)
+}
+
+// REDC calculates t*R⁻¹ mod m for the 128-bit number t = hi*2^64 + lo using Montgomery reduction. t must be smaller
+// than m*R (i.e. hi must be smaller than m), otherwise REDC panics.
+// REDC adds a multiple u*m of the modulus to t, so that t+u*m is divisible by R without changing its residue
+// modulo m. u = t*(-m⁻¹) mod R is chosen, so that t+u*m ≡ t-t*m⁻¹*m ≡ 0 mod R. The result (t+u*m)/R is smaller
+// than 2m, so a single subtraction is sufficient for the final reduction.
+func (mt *Montgomery) REDC(hi, lo uint64) uint64 {
+  if hi >= mt.mod {
+    panic("grypto/modular: REDC input must be smaller than modulus*2^64")
+  }
+
+  u := lo * mt.modInv
+  uHi, uLo := bits.Mul64(u, mt.mod)
+
+  // t+u*m, the lower 64 bits are 0 by construction, so only the carry is needed
+  _, carry := bits.Add64(lo, uLo, 0)
+  t, carry := bits.Add64(hi, uHi, carry)
+
+  // the result might overflow 64 bits for moduli greater than 2^63, subtracting m wraps around correctly in this case
+  if carry != 0 || t >= mt.mod {
+    t -= mt.mod
+  }
+  return t
+}
+
+// Mul multiplies a and b, which must both be in Montgomery form. The result is also in Montgomery form.
+func (mt *Montgomery) Mul(a, b uint64) uint64 {
+  return mt.REDC(bits.Mul64(a, b))
+}
+
+// Exp calculates base ^ exp mod m using the square-and-multiply method with Montgomery multiplication. base and the
+// result are in the usual representation, the conversion to and from Montgomery form is done by Exp.
+// It returns the same results as Pow64.
+func (mt *Montgomery) Exp(base, exp uint64) uint64 {
+  if base == 0 && exp == 0 {
+    panic("grypto/modular: 0^0 is not defined")
+  }
+
+  // special cases
+  if base == 0 {
+    return 0
+  }
+  if exp == 0 {
+    return 1
+  }
+
+  var (
+    x = mt.r1
+    a = mt.ToMontgomery(base)
+  )
+  mt.Trace.printf("R = 2^64, R mod %d = %d, R² mod %d = %d", mt.mod, mt.r1, mt.mod, mt.r2)
+  mt.Trace.printf("convert %d to Montgomery form: %d*R mod %d = %d", base, base, mt.mod, a)
+
+  for i := 0; exp > 0; i++ {
+    if exp&1 == 1 {
+      x = mt.Mul(x, a) // multiply
+      if mt.Trace != nil {
+        mt.Trace.printf("bit %d = 1: x̃ = REDC(x̃*ã) = %d (x = %d)", i, x, mt.FromMontgomery(x))
+      }
+    }
+
+    exp >>= 1
+    if exp > 0 {
+      a = mt.Mul(a, a) // square
+      if mt.Trace != nil {
+        mt.Trace.printf("square: ã = REDC(ã*ã) = %d (a = %d)", a, mt.FromMontgomery(a))
+      }
+    }
+  }
+
+  result := mt.FromMontgomery(x)
+  mt.Trace.printf("convert from Montgomery form: REDC(%d) = %d", x, result)
+  return result
+}
+
+// MontgomeryBig is like Montgomery but for arbitrarily large odd moduli. R = 2^k is chosen as the smallest power of
+// 2 greater than the modulus. Note that MontgomeryBig allocates new big.Ints for all intermediate values, so it is
+// meant for following the algorithm rather than for performance (big.Int.Exp already uses Montgomery multiplication
+// internally for odd moduli).
+type MontgomeryBig struct {
+  // Trace is called with the intermediate values of Exp, if it is not nil.
+  Trace TraceFunc
+
+  mod *big.Int
+  // k is the bit length of R-1, i.e. R = 2^k
+  k uint
+  // mask is R-1, used for reducing modulo R
+  mask *big.Int
+  // modInv is -m⁻¹ mod R
+  modInv *big.Int
+  // r1 is R mod m, i.e. 1 in Montgomery form
+  r1 *big.Int
+  // r2 is R² mod m, used for converting numbers to Montgomery form
+  r2 *big.Int
+}
+
+// NewMontgomeryBig returns a new Montgomery context for the given modulus. mod must be odd and greater than 1,
+// otherwise NewMontgomeryBig panics.
+func NewMontgomeryBig(mod *big.Int) *MontgomeryBig {
+  if mod.Bit(0) == 0 || mod.Cmp(big.NewInt(1)) <= 0 {
+    panic("grypto/modular: modulus must be odd and greater than 1")
+  }
+
+  var (
+    k    = uint(mod.BitLen())
+    r    = new(big.Int).Lsh(big.NewInt(1), k)
+    mask = new(big.Int).Sub(r, big.NewInt(1))
+  )
+
+  // m is odd, so it is invertible modulo R
+  modInv := new(big.Int).ModInverse(mod, r)
+  modInv.Sub(r, modInv)
+
+  r1 := new(big.Int).Mod(r, mod)
+  r2 := new(big.Int).Mul(r1, r1)
+  r2.Mod(r2, mod)
+
+  return &MontgomeryBig{
+    mod:    new(big.Int).Set(mod),
+    k:      k,
+    mask:   mask,
+    modInv: modInv,
+    r1:     r1,
+    r2:     r2,
+  }
+}
+
+// Modulus returns the modulus of the Montgomery context.
+func (mt *MontgomeryBig) Modulus() *big.Int {
+  return new(big.Int).Set(mt.mod)
+}
+
+// ToMontgomery converts a to Montgomery form, i.e. it returns a*R mod m. Negative numbers are normalized first.
+func (mt *MontgomeryBig) ToMontgomery(a *big.Int) *big.Int {
+  t := new(big.Int).Mod(a, mt.mod)
+  return mt.REDC(t.Mul(t, mt.r2))
+}
+
+// FromMontgomery converts ã from Montgomery form back to the usual representation, i.e. it returns ã*R⁻¹ mod m.
+func (mt *MontgomeryBig) FromMontgomery(a *big.Int) *big.Int {
+  return mt.REDC(a)
+}
+
+// REDC calculates t*R⁻¹ mod m using Montgomery reduction (see Montgomery.REDC). t must not be negative and must be
+// smaller than m*R, otherwise REDC panics.
+func (mt *MontgomeryBig) REDC(t *big.Int) *big.Int {
+  if t.Sign() < 0 || t.Cmp(new(big.Int).Lsh(mt.mod, mt.k)) >= 0 {
+    panic("grypto/modular: REDC input must be in range [0, modulus*R)")
+  }
+
+  // u = (t mod R) * (-m⁻¹) mod R
+  u := new(big.Int).And(t, mt.mask)
+  u.Mul(u, mt.modInv).And(u, mt.mask)
+
+  // (t + u*m) / R
+  u.Mul(u, mt.mod).Add(u, t).Rsh(u, mt.k)
+  if u.Cmp(mt.mod) >= 0 {
+    u.Sub(u, mt.mod)
+  }
+  return u
+}
+
+// Mul multiplies a and b, which must both be in Montgomery form. The result is also in Montgomery form.
+func (mt *MontgomeryBig) Mul(a, b *big.Int) *big.Int {
+  return mt.REDC(new(big.Int).Mul(a, b))
+}
+
+// Exp calculates base ^ exp mod m using the square-and-multiply method with Montgomery multiplication (see
+// Montgomery.Exp). It returns the same results as PowBig.
+func (mt *MontgomeryBig) Exp(base, exp *big.Int) *big.Int {
+  if exp.Sign() < 0 {
+    panic("grypto/modular: negative exponent not allowed")
+  }
+  if base.Sign() == 0 && exp.Sign() == 0 {
+    panic("grypto/modular: 0^0 is not defined")
+  }
+
+  // special cases
+  if base.Sign() == 0 {
+    return new(big.Int)
+  }
+  if exp.Sign() == 0 {
+    return big.NewInt(1)
+  }
+
+  var (
+    x = new(big.Int).Set(mt.r1)
+    a = mt.ToMontgomery(base)
+  )
+  mt.Trace.printf("R = 2^%d, R mod %s = %s, R² mod %s = %s", mt.k, mt.mod, mt.r1, mt.mod, mt.r2)
+  mt.Trace.printf("convert %s to Montgomery form: %s*R mod %s = %s", base, base, mt.mod, a)
+
+  for i := 0; i < exp.BitLen(); i++ {
+    if exp.Bit(i) == 1 {
+      x = mt.Mul(x, a) // multiply
+      if mt.Trace != nil {
+        mt.Trace.printf("bit %d = 1: x̃ = REDC(x̃*ã) = %s (x = %s)", i, x, mt.FromMontgomery(x))
+      }
+    }
+
+    if i < exp.BitLen()-1 {
+      a = mt.Mul(a, a) // square
+      if mt.Trace != nil {
+        mt.Trace.printf("square: ã = REDC(ã*ã) = %s (a = %s)", a, mt.FromMontgomery(a))
+      }
+    }
+  }
+
+  result := mt.FromMontgomery(x)
+  mt.Trace.printf("convert from Montgomery form: REDC(%s) = %s", x, result)
+  return result
+}
@@ -0,0 +1,193 @@
+package modular_test
+
+import (
+  "fmt"
+  "math"
+  "math/big"
+  "testing"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/modular"
+)
+
+var _ = Describe("Montgomery", func() {
+  It("should panic on invalid moduli", func() {
+    test := func(m uint64) {
+      ExpectWithOffset(1, func() {
+        modular.NewMontgomery(m)
+      }).To(Panic())
+    }
+
+    test(0)
+    test(1)
+    test(2)
+    test(1 << 63)
+  })
+
+  It("should panic on invalid inputs", func() {
+    mt := modular.NewMontgomery(561)
+    Expect(func() { mt.Exp(0, 0) }).To(Panic())
+    Expect(func() { mt.REDC(561, 0) }).To(Panic())
+  })
+
+  moduli := []uint64{3, 5, 561, math.MaxInt32, 1<<32 + 1, 1<<61 - 1, 1<<63 + 1, math.MaxUint64 - 58, math.MaxUint64}
+
+  It("should convert to and from Montgomery form", func() {
+    for _, m := range moduli {
+      mt := modular.NewMontgomery(m)
+      Expect(mt.Modulus()).To(Equal(m))
+
+      for _, a := range []uint64{0, 1, 2, m / 2, m - 1, m, m + 1, math.MaxUint64} {
+        r := mt.ToMontgomery(a)
+        // a*R mod m with R = 2^64
+        expected := new(big.Int).Lsh(new(big.Int).SetUint64(a), 64)
+        expected.Mod(expected, new(big.Int).SetUint64(m))
+        Expect(r).To(Equal(expected.Uint64()), "%d * R mod %d", a, m)
+        Expect(mt.FromMontgomery(r)).To(Equal(a%m), "%d mod %d", a, m)
+      }
+    }
+  })
+
+  It("should return the same results as MulMod64", func() {
+    for _, m := range moduli {
+      mt := modular.NewMontgomery(m)
+      for _, a := range []uint64{0, 1, 2, 3, m / 3, m - 2, m - 1} {
+        for _, b := range []uint64{0, 1, 2, 3, m / 2, m - 2, m - 1} {
+          p := mt.FromMontgomery(mt.Mul(mt.ToMontgomery(a), mt.ToMontgomery(b)))
+          Expect(p).To(Equal(modular.MulMod64(a, b, m)), "%d * %d mod %d", a, b, m)
+        }
+      }
+    }
+  })
+
+  It("should return the same results as Pow64", func() {
+    for m := uint64(3); m <= 61; m += 2 {
+      mt := modular.NewMontgomery(m)
+      for b := uint64(0); b <= m; b++ {
+        for e := uint64(0); e <= 40; e++ {
+          if b == 0 && e == 0 {
+            continue
+          }
+          Expect(mt.Exp(b, e)).To(Equal(modular.Pow64(b, e, m)), "%d ^ %d mod %d", b, e, m)
+        }
+      }
+    }
+
+    for _, m := range moduli {
+      mt := modular.NewMontgomery(m)
+      for _, b := range []uint64{2, 3, m - 1, math.MaxUint64 - 3} {
+        for _, e := range []uint64{1, 2, 65537, m - 1, math.MaxUint64} {
+          Expect(mt.Exp(b, e)).To(Equal(modular.Pow64(b, e, m)), "%d ^ %d mod %d", b, e, m)
+        }
+      }
+    }
+  })
+
+  It("should trace the intermediate values", func() {
+    var lines []string
+    mt := modular.NewMontgomery(13)
+    mt.Trace = func(format string, a ...interface{}) {
+      lines = append(lines, fmt.Sprintf(format, a...))
+    }
+
+    Expect(mt.Exp(2, 5)).To(Equal(uint64(6)))
+    Expect(lines).To(Equal([]string{
+      "R = 2^64, R mod 13 = 3, R² mod 13 = 9",
+      "convert 2 to Montgomery form: 2*R mod 13 = 6",
+      "bit 0 = 1: x̃ = REDC(x̃*ã) = 6 (x = 2)",
+      "square: ã = REDC(ã*ã) = 12 (a = 4)",
+      "square: ã = REDC(ã*ã) = 9 (a = 3)",
+      "bit 2 = 1: x̃ = REDC(x̃*ã) = 5 (x = 6)",
+      "convert from Montgomery form: REDC(5) = 6",
+    }))
+  })
+})
+
+var _ = Describe("MontgomeryBig", func() {
+  It("should panic on invalid moduli", func() {
+    test := func(m int64) {
+      ExpectWithOffset(1, func() {
+        modular.NewMontgomeryBig(big.NewInt(m))
+      }).To(Panic())
+    }
+
+    test(-3)
+    test(0)
+    test(1)
+    test(2)
+  })
+
+  It("should panic on invalid inputs", func() {
+    mt := modular.NewMontgomeryBig(big.NewInt(561))
+    Expect(func() { mt.Exp(big.NewInt(0), big.NewInt(0)) }).To(Panic())
+    Expect(func() { mt.Exp(big.NewInt(2), big.NewInt(-1)) }).To(Panic())
+    Expect(func() { mt.REDC(big.NewInt(-1)) }).To(Panic())
+    Expect(func() { mt.REDC(big.NewInt(561 * 1024)) }).To(Panic())
+  })
+
+  It("should return the same results as PowBig", func() {
+    for m := int64(3); m <= 61; m += 2 {
+      mt := modular.NewMontgomeryBig(big.NewInt(m))
+      Expect(mt.Modulus()).To(Equal(big.NewInt(m)))
+
+      for b := -m; b <= m; b++ {
+        for e := int64(0); e <= 40; e++ {
+          if b == 0 && e == 0 {
+            continue
+          }
+          base, exp := big.NewInt(b), big.NewInt(e)
+          pow := mt.Exp(base, exp)
+          Expect(pow.Cmp(modular.PowBig(base, exp, big.NewInt(m)))).To(Equal(0), "%d ^ %d mod %d", b, e, m)
+        }
+      }
+    }
+  })
+
+  It("should correctly calculate modular exponentiation of large integers", func() {
+    m, _ := new(big.Int).SetString("170141183460469231731687303715884105727", 10) // 2^127-1
+    b, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+    e, _ := new(big.Int).SetString("98765432109876543210987654321", 10)
+
+    mt := modular.NewMontgomeryBig(m)
+    Expect(mt.Exp(b, e)).To(Equal(new(big.Int).Exp(b, e, m)))
+    Expect(mt.FromMontgomery(mt.Mul(mt.ToMontgomery(b), mt.ToMontgomery(e)))).To(
+      Equal(new(big.Int).Mod(new(big.Int).Mul(b, e), m)))
+  })
+})
+
+func BenchmarkMontgomeryExpMaxInt32(b *testing.B) {
+  var (
+    r  uint64
+    mt = modular.NewMontgomery(math.MaxInt32)
+  )
+  for n := 0; n < b.N; n++ {
+    r = mt.Exp(math.MaxInt32-3, math.MaxInt32)
+  }
+  result64 = r
+}
+
+func BenchmarkMontgomeryExpMaxUint64(b *testing.B) {
+  var (
+    r  uint64
+    mt = modular.NewMontgomery(math.MaxUint64 - 58)
+  )
+  for n := 0; n < b.N; n++ {
+    r = mt.Exp(math.MaxUint64-3, math.MaxUint64)
+  }
+  result64 = r
+}
+
+func BenchmarkMontgomeryBigExpMaxInt32(b *testing.B) {
+  var (
+    r    *big.Int
+    base = big.NewInt(math.MaxInt32 - 3)
+    exp  = big.NewInt(math.MaxInt32)
+    mt   = modular.NewMontgomeryBig(big.NewInt(math.MaxInt32))
+  )
+  for n := 0; n < b.N; n++ {
+    r = mt.Exp(base, exp)
+  }
+  resultBig = r
+}
@@ -1,5 +1,7 @@
 package modular
 
+import "math/big"
+
 // OrderOf calculates the order of base in the residue system modulo mod.
 // In ℤₐ the order of an element g is defined as the smallest integer l, so that g^l ≡ 1 mod a, if such l exists.
 // If not, the order of g is infinite.
@@ -35,3 +37,58 @@ func OrderOf(base, mod int32) (order int32, inf bool) {
 
   return i, false
 }
+
+// OrderOfBig is like OrderOf but for arbitrarily large integers.
+// Note that it enumerates all powers of base, so it is only practicable for elements with small order.
+func OrderOfBig(base, mod *big.Int) (order *big.Int, inf bool) {
+  if base.Sign() <= 0 {
+    panic("grypto/modular: base must be greater than 0")
+  }
+  if mod.Sign() <= 0 {
+    panic("grypto/modular: modulus must be greater than 0")
+  }
+
+  var (
+    exp = big.NewInt(1)
+    b   = new(big.Int).Mod(base, mod)
+    one = big.NewInt(1)
+  )
+
+  for order = new(big.Int); order.Cmp(mod) <= 0; order.Add(order, one) {
+    if order.Sign() > 0 && exp.Cmp(one) == 0 {
+      break
+    }
+    if exp.Sign() == 0 {
+      // once base^i = 0, it won't change anymore =>  order(base) = Inf
+      return nil, true
+    }
+
+    exp.Mul(exp, b).Mod(exp, mod)
+  }
+
+  return order, false
+}
+
+// OrderOf64 is like OrderOf but for uint64 numbers. As enumerating all powers of base is impractical for 64-bit
+// numbers, it uses the fact, that the order of a unit divides φ(mod) (Lagrange's theorem). It factors φ(mod) and
+// removes all prime factors p from φ(mod) as long as base^(φ(mod)/p) ≡ 1 mod mod.
+// Elements, which are not coprime to mod, have infinite order, as no power of them can be 1.
+// Note that the factorization uses trial division, so it is only fast, if mod and φ(mod) don't have more than one
+// large prime factor.
+func OrderOf64(base, mod uint64) (order uint64, inf bool) {
+  if base == 0 {
+    panic("grypto/modular: base must be greater than 0")
+  }
+  if mod == 0 {
+    panic("grypto/modular: modulus must be greater than 0")
+  }
+
+  if mod == 1 {
+    return 1, false
+  }
+  if gcd64(base%mod, mod) != 1 {
+    return 0, true
+  }
+
+  return orderOfUnit64(base%mod, mod), false
+}
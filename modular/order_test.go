@@ -1,9 +1,12 @@
 package modular_test
 
 import (
+  "math/big"
+
   . "github.com/onsi/ginkgo"
   . "github.com/onsi/gomega"
 
+  "github.com/timebertt/grypto/euclid"
   "github.com/timebertt/grypto/modular"
 )
 
@@ -40,3 +43,94 @@ var _ = Describe("OrderOf", func() {
     test(2, 8, 0, true)
   })
 })
+
+var _ = Describe("OrderOfBig", func() {
+  It("should panic on invalid inputs", func() {
+    test := func(b, m int64) {
+      ExpectWithOffset(1, func() {
+        modular.OrderOfBig(big.NewInt(b), big.NewInt(m))
+      }).To(Panic())
+    }
+
+    test(-1, 2)
+    test(1, -1)
+    test(0, 2)
+    test(1, 0)
+  })
+
+  It("should return the same results as OrderOf", func() {
+    for m := int32(1); m <= 100; m++ {
+      for b := int32(1); b <= m; b++ {
+        expected, expectedInf := modular.OrderOf(b, m)
+        o, inf := modular.OrderOfBig(big.NewInt(int64(b)), big.NewInt(int64(m)))
+        Expect(inf).To(Equal(expectedInf), "order(%d) mod %d", b, m)
+        if !expectedInf {
+          Expect(o.Int64()).To(Equal(int64(expected)), "order(%d) mod %d", b, m)
+        }
+      }
+    }
+  })
+
+  It("should correctly calculate order for large moduli", func() {
+    // 2^64+1 = 274177 * 67280421310721, so the order of 2 is 128
+    m, _ := new(big.Int).SetString("18446744073709551617", 10)
+    o, inf := modular.OrderOfBig(big.NewInt(2), m)
+    Expect(inf).To(BeFalse())
+    Expect(o).To(Equal(big.NewInt(128)))
+  })
+})
+
+var _ = Describe("OrderOf64", func() {
+  It("should panic on invalid inputs", func() {
+    test := func(b, m uint64) {
+      ExpectWithOffset(1, func() {
+        modular.OrderOf64(b, m)
+      }).To(Panic())
+    }
+
+    test(0, 2)
+    test(1, 0)
+  })
+
+  It("should return the same results as OrderOf for units", func() {
+    for m := int32(2); m <= 200; m++ {
+      for b := int32(1); b <= m; b++ {
+        expected, expectedInf := modular.OrderOf(b, m)
+        o, inf := modular.OrderOf64(uint64(b), uint64(m))
+        if expectedInf {
+          // OrderOf only detects infinite order of elements, that reach 0
+          Expect(inf).To(BeTrue(), "order(%d) mod %d", b, m)
+          continue
+        }
+        if euclid.GreatestCommonDivisor(int(b), int(m)) != 1 {
+          Expect(inf).To(BeTrue(), "order(%d) mod %d", b, m)
+          continue
+        }
+        Expect(inf).To(BeFalse(), "order(%d) mod %d", b, m)
+        Expect(o).To(Equal(uint64(expected)), "order(%d) mod %d", b, m)
+      }
+    }
+  })
+
+  It("should correctly calculate order for 64-bit moduli", func() {
+    test := func(b, m, expected uint64) {
+      o, inf := modular.OrderOf64(b, m)
+      ExpectWithOffset(1, inf).To(BeFalse())
+      ExpectWithOffset(1, o).To(Equal(expected))
+    }
+
+    // 2^32+1 = 641 * 6700417, order of 2 is 64
+    test(2, 1<<32+1, 64)
+    // 2 is a primitive root modulo 3^40 > 2^63, so its order is φ(3^40) = 2 * 3^39
+    test(2, 12157665459056928801, 8105110306037952534)
+    test(12157665459056928800, 12157665459056928801, 2)
+    test(4, 12157665459056928801, 4052555153018976267)
+  })
+
+  It("should detect infinite order", func() {
+    _, inf := modular.OrderOf64(3, 12157665459056928801)
+    Expect(inf).To(BeTrue())
+    _, inf = modular.OrderOf64(6, 1<<63)
+    Expect(inf).To(BeTrue())
+  })
+})
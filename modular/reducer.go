@@ -0,0 +1,83 @@
+package modular
+
+// Reducer implements modular multiplication for a fixed uint64 modulus. Implementations might use a different
+// internal representation of the residues (e.g. Montgomery form), so all numbers need to be converted before
+// multiplying them and the result needs to be reverted afterwards.
+// Reducer allows to exchange the reduction strategy used in PowWith, e.g. for comparing the performance of naive
+// reduction using %, Barrett reduction and Montgomery reduction.
+type Reducer interface {
+  // Modulus returns the modulus of the Reducer.
+  Modulus() uint64
+  // Convert converts a to the internal representation of the Reducer.
+  Convert(a uint64) uint64
+  // Revert converts a from the internal representation of the Reducer back to the usual representation.
+  Revert(a uint64) uint64
+  // Mul multiplies a and b, which must both be in the internal representation of the Reducer.
+  // The result is also in the internal representation.
+  Mul(a, b uint64) uint64
+}
+
+var (
+  _ Reducer = NaiveReducer(1)
+  _ Reducer = &Barrett{}
+  _ Reducer = &Montgomery{}
+)
+
+// NaiveReducer is a Reducer, that reduces all products using the % operator (see MulMod64). It doesn't need any
+// precomputation and uses the usual representation of residues.
+type NaiveReducer uint64
+
+// Modulus returns the modulus of the NaiveReducer.
+func (n NaiveReducer) Modulus() uint64 {
+  return uint64(n)
+}
+
+// Convert reduces a modulo m.
+func (n NaiveReducer) Convert(a uint64) uint64 {
+  return a % uint64(n)
+}
+
+// Revert returns a unchanged, as NaiveReducer uses the usual representation of residues.
+func (n NaiveReducer) Revert(a uint64) uint64 {
+  return a
+}
+
+// Mul calculates a*b mod m using MulMod64.
+func (n NaiveReducer) Mul(a, b uint64) uint64 {
+  return MulMod64(a, b, uint64(n))
+}
+
+// PowWith is like Pow64 but uses the given Reducer for all multiplications. It returns the same results as Pow64.
+func PowWith(base, exp uint64, r Reducer) uint64 {
+  if r.Modulus() == 0 {
+    panic("grypto/modular: modulus must be greater than 0")
+  }
+  if base == 0 && exp == 0 {
+    panic("grypto/modular: 0^0 is not defined")
+  }
+
+  // special cases
+  if base == 0 {
+    return 0
+  }
+  if exp == 0 {
+    return 1
+  }
+
+  // square-and-multiply (fast exponentiation)
+  var (
+    x = r.Convert(1)
+    a = r.Convert(base)
+  )
+
+  for exp > 0 {
+    if exp&1 == 1 {
+      x = r.Mul(x, a) // multiply
+    }
+
+    a = r.Mul(a, a) // square
+    exp >>= 1
+  }
+
+  return r.Revert(x)
+}
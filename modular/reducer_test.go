@@ -0,0 +1,56 @@
+package modular_test
+
+import (
+  "math"
+  "testing"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/modular"
+)
+
+var _ = Describe("PowWith", func() {
+  It("should panic on invalid inputs", func() {
+    Expect(func() { modular.PowWith(2, 3, modular.NaiveReducer(0)) }).To(Panic())
+    Expect(func() { modular.PowWith(0, 0, modular.NaiveReducer(5)) }).To(Panic())
+  })
+
+  It("should return the same results as Pow64 for all reducers", func() {
+    for _, m := range []uint64{3, 5, 561, math.MaxInt32, 1<<61 - 1, math.MaxUint64 - 58} {
+      reducers := []modular.Reducer{modular.NaiveReducer(m), modular.NewBarrett(m), modular.NewMontgomery(m)}
+
+      for _, r := range reducers {
+        Expect(r.Modulus()).To(Equal(m))
+        for _, b := range []uint64{0, 1, 2, 3, m - 1, m, math.MaxUint64 - 3} {
+          for _, e := range []uint64{0, 1, 2, 65537, m - 1, math.MaxUint64} {
+            if b == 0 && e == 0 {
+              continue
+            }
+            Expect(modular.PowWith(b, e, r)).To(Equal(modular.Pow64(b, e, m)), "%T: %d ^ %d mod %d", r, b, e, m)
+          }
+        }
+      }
+    }
+  })
+})
+
+func benchmarkPowWith(b *testing.B, r modular.Reducer) {
+  var res uint64
+  for n := 0; n < b.N; n++ {
+    res = modular.PowWith(math.MaxUint64-3, math.MaxUint64, r)
+  }
+  result64 = res
+}
+
+func BenchmarkPowWithNaiveReducer(b *testing.B) {
+  benchmarkPowWith(b, modular.NaiveReducer(math.MaxUint64-58))
+}
+
+func BenchmarkPowWithBarrett(b *testing.B) {
+  benchmarkPowWith(b, modular.NewBarrett(math.MaxUint64-58))
+}
+
+func BenchmarkPowWithMontgomery(b *testing.B) {
+  benchmarkPowWith(b, modular.NewMontgomery(math.MaxUint64-58))
+}
@@ -1,5 +1,7 @@
 package modular
 
+import "math/big"
+
 // SubgroupMaxMod is the maximum mod value accepted in SubgroupOf.
 const SubgroupMaxMod = 1 << 22
 
@@ -41,3 +43,39 @@ func SubgroupOf(base, mod int32) []int32 {
 
   return g
 }
+
+// SubgroupOfBig is like SubgroupOf but for arbitrarily large integers. The modulus is still limited to SubgroupMaxMod,
+// but base might be larger than that.
+func SubgroupOfBig(base, mod *big.Int) []*big.Int {
+  if base.Sign() <= 0 {
+    panic("grypto/modular: base must be greater than 0")
+  }
+  if mod.Sign() <= 0 {
+    panic("grypto/modular: modulus must be greater than 0")
+  }
+  if mod.Cmp(big.NewInt(SubgroupMaxMod)) > 0 {
+    // be conservative here, this slice implementation is very inefficient and can quickly become a memory hog
+    panic("grypto/modular: modulus too large")
+  }
+
+  var (
+    g   []*big.Int
+    exp = big.NewInt(1)
+    b   = new(big.Int).Mod(base, mod)
+    one = big.NewInt(1)
+  )
+
+  for i := new(big.Int); i.Cmp(mod) <= 0; i.Add(i, one) {
+    if i.Sign() > 0 && exp.Cmp(one) == 0 {
+      break
+    }
+    g = append(g, new(big.Int).Set(exp))
+    if exp.Sign() == 0 {
+      return g // exponentiation won't change anymore
+    }
+
+    exp.Mul(exp, b).Mod(exp, mod)
+  }
+
+  return g
+}
@@ -2,6 +2,7 @@ package modular_test
 
 import (
   "math"
+  "math/big"
 
   . "github.com/onsi/ginkgo"
   . "github.com/onsi/gomega"
@@ -39,3 +40,32 @@ var _ = Describe("SubgroupOf", func() {
     test(2, 8, []int32{1, 2, 4, 0})
   })
 })
+
+var _ = Describe("SubgroupOfBig", func() {
+  It("should panic on invalid inputs", func() {
+    test := func(b, m int64) {
+      ExpectWithOffset(1, func() {
+        modular.SubgroupOfBig(big.NewInt(b), big.NewInt(m))
+      }).To(Panic())
+    }
+
+    test(-1, 2)
+    test(1, -1)
+    test(0, 2)
+    test(1, 0)
+    test(1, math.MaxInt32)
+  })
+
+  It("should return the same results as SubgroupOf", func() {
+    for m := int32(1); m <= 100; m++ {
+      for b := int32(1); b <= m; b++ {
+        expected := modular.SubgroupOf(b, m)
+        s := modular.SubgroupOfBig(big.NewInt(int64(b)), big.NewInt(int64(m)))
+        Expect(s).To(HaveLen(len(expected)), "subgroup of %d mod %d", b, m)
+        for i := range s {
+          Expect(s[i].Int64()).To(Equal(int64(expected[i])), "subgroup of %d mod %d", b, m)
+        }
+      }
+    }
+  })
+})
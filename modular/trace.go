@@ -0,0 +1,13 @@
+package modular
+
+// TraceFunc can be passed to algorithms supporting tracing to follow the intermediate steps of the calculation.
+// It is called with a format string and arguments like fmt.Printf, once for each step. The format string doesn't
+// end with a newline.
+type TraceFunc func(format string, a ...interface{})
+
+// printf calls the TraceFunc, if it is not nil.
+func (t TraceFunc) printf(format string, a ...interface{}) {
+  if t != nil {
+    t(format, a...)
+  }
+}
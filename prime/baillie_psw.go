@@ -0,0 +1,287 @@
+package prime
+
+import (
+  "math"
+  "math/big"
+  "math/bits"
+
+  "github.com/timebertt/grypto/modular"
+)
+
+// smallPrimes are used for trial division before running the more expensive probable prime tests.
+var smallPrimes = []uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47}
+
+// IsPrimeBailliePSW64 tests p for primality using the Baillie-PSW primality test.
+// After trial division by some small primes, it combines a strong probable prime test to base 2 (a single round of
+// the Miller-Rabin primality test, see IsStrongProbablePrime64) with a strong Lucas probable prime test (see
+// IsStrongLucasProbablePrime64). Both tests are fooled by different kinds of composite numbers, and there is no known
+// composite number, that passes both of them. For uint64 numbers, it has been verified, that there is no such number,
+// so the result is exact.
+// In contrast to the deterministic Miller-Rabin primality test, which needs up to 12 rounds for uint64 numbers, the
+// Baillie-PSW primality test only needs the equivalent of about 3 rounds. It is also used by big.Int.ProbablyPrime.
+// See: https://en.wikipedia.org/wiki/Baillie-PSW_primality_test
+func IsPrimeBailliePSW64(p uint64) bool {
+  if p <= 1 {
+    // 1 is neither prime nor composite
+    return false
+  }
+
+  for _, q := range smallPrimes {
+    if p%q == 0 {
+      return p == q
+    }
+  }
+
+  return IsStrongProbablePrime64(p, 2) && IsStrongLucasProbablePrime64(p)
+}
+
+// IsPrimeBailliePSWBig is like IsPrimeBailliePSW64 but for arbitrarily large integers. For numbers greater than
+// MaxUint64, the result is only probably correct, though no counterexample is known.
+func IsPrimeBailliePSWBig(p *big.Int) bool {
+  if p.Cmp(big.NewInt(1)) <= 0 {
+    // 1 is neither prime nor composite
+    return false
+  }
+
+  r := new(big.Int)
+  for _, q := range smallPrimes {
+    if r.Mod(p, new(big.Int).SetUint64(q)).Sign() == 0 {
+      return p.IsUint64() && p.Uint64() == q
+    }
+  }
+
+  return IsStrongProbablePrimeBig(p, big.NewInt(2)) && IsStrongLucasProbablePrimeBig(p)
+}
+
+// IsStrongLucasProbablePrime64 tests if p is a strong Lucas probable prime with parameters chosen by Selfridge's
+// method A: D is the first number in the sequence 5, -7, 9, -11, 13, ... with Jacobi symbol (D/p) = -1, P = 1 and
+// Q = (1-D)/4. The test writes p+1 as 2^s * d with d odd and checks if U_d ≡ 0 mod p or V_(2^r * d) ≡ 0 mod p for
+// some r < s, where U_k and V_k are the Lucas sequences with parameters P and Q. This must be true if p is prime.
+// Composite numbers, that pass the test, are called strong Lucas pseudoprimes (e.g. 5459). For perfect squares, there
+// is no D with (D/p) = -1, so IsStrongLucasProbablePrime64 directly returns false for them.
+// See: https://en.wikipedia.org/wiki/Lucas_pseudoprime#Strong_Lucas_pseudoprimes
+func IsStrongLucasProbablePrime64(p uint64) bool {
+  if p <= 1 {
+    return false
+  }
+  if p%2 == 0 {
+    return p == 2
+  }
+  if isSquare64(p) {
+    return false
+  }
+
+  // Selfridge's method A
+  d := int64(5)
+  for {
+    j := jacobi64(d, p)
+    if j == -1 {
+      break
+    }
+    if j == 0 {
+      // gcd(D, p) > 1, so p is composite, unless p = |D|
+      return p == abs64(d)
+    }
+
+    if d > 0 {
+      d = -(d + 2)
+    } else {
+      d = -d + 2
+    }
+  }
+
+  var (
+    dMod = residue64(d, p)
+    qMod = residue64((1-d)/4, p)
+  )
+
+  // write p+1 as 2^s * k with k odd, p+1 might overflow
+  var (
+    s = 1
+    k = p>>1 + 1
+  )
+  for k%2 == 0 {
+    s++
+    k /= 2
+  }
+
+  // calculate U_k, V_k and Q^k by processing the bits of k from left to right, starting with U_1 = 1, V_1 = P = 1
+  u, v, qk := uint64(1), uint64(1), qMod
+  for i := bits.Len64(k) - 2; i >= 0; i-- {
+    // double the index: U_2k = U_k * V_k, V_2k = V_k² - 2Q^k
+    u = modular.MulMod64(u, v, p)
+    v = modular.SubMod64(modular.MulMod64(v, v, p), modular.AddMod64(qk, qk, p), p)
+    qk = modular.MulMod64(qk, qk, p)
+
+    if k>>uint(i)&1 == 1 {
+      // increment the index: U_(k+1) = (P*U_k + V_k)/2, V_(k+1) = (D*U_k + P*V_k)/2
+      u, v = half64(modular.AddMod64(u, v, p), p), half64(modular.AddMod64(modular.MulMod64(dMod, u, p), v, p), p)
+      qk = modular.MulMod64(qk, qMod, p)
+    }
+  }
+
+  if u == 0 || v == 0 {
+    return true
+  }
+  for r := 1; r < s; r++ {
+    // V_2k = V_k² - 2Q^k
+    v = modular.SubMod64(modular.MulMod64(v, v, p), modular.AddMod64(qk, qk, p), p)
+    if v == 0 {
+      return true
+    }
+    qk = modular.MulMod64(qk, qk, p)
+  }
+
+  return false
+}
+
+// IsStrongLucasProbablePrimeBig is like IsStrongLucasProbablePrime64 but for arbitrarily large integers.
+func IsStrongLucasProbablePrimeBig(p *big.Int) bool {
+  var (
+    one = big.NewInt(1)
+    two = big.NewInt(2)
+  )
+
+  if p.Cmp(one) <= 0 {
+    return false
+  }
+  if p.Bit(0) == 0 {
+    return p.Cmp(two) == 0
+  }
+  if sqrt := new(big.Int).Sqrt(p); sqrt.Mul(sqrt, sqrt).Cmp(p) == 0 {
+    return false
+  }
+
+  // Selfridge's method A
+  d := big.NewInt(5)
+  for {
+    j := big.Jacobi(d, p)
+    if j == -1 {
+      break
+    }
+    if j == 0 {
+      // gcd(D, p) > 1, so p is composite, unless p = |D|
+      return new(big.Int).Abs(d).Cmp(p) == 0
+    }
+
+    if d.Sign() > 0 {
+      d.Add(d, two).Neg(d)
+    } else {
+      d.Neg(d).Add(d, two)
+    }
+  }
+
+  var (
+    dMod = new(big.Int).Mod(d, p)
+    // Q = (1-D)/4, D ≡ 1 mod 4 for all D in the sequence
+    qMod = new(big.Int).Sub(one, d)
+  )
+  qMod.Quo(qMod, big.NewInt(4)).Mod(qMod, p)
+
+  // write p+1 as 2^s * k with k odd
+  k := new(big.Int).Add(p, one)
+  s := k.TrailingZeroBits()
+  k.Rsh(k, s)
+
+  // calculate U_k, V_k and Q^k by processing the bits of k from left to right, starting with U_1 = 1, V_1 = P = 1
+  var (
+    u  = big.NewInt(1)
+    v  = big.NewInt(1)
+    qk = new(big.Int).Set(qMod)
+    t  = new(big.Int)
+  )
+  for i := k.BitLen() - 2; i >= 0; i-- {
+    // double the index: U_2k = U_k * V_k, V_2k = V_k² - 2Q^k
+    u.Mul(u, v).Mod(u, p)
+    v.Mul(v, v).Sub(v, t.Lsh(qk, 1)).Mod(v, p)
+    qk.Mul(qk, qk).Mod(qk, p)
+
+    if k.Bit(i) == 1 {
+      // increment the index: U_(k+1) = (P*U_k + V_k)/2, V_(k+1) = (D*U_k + P*V_k)/2
+      t.Mul(dMod, u).Add(t, v)
+      halfBig(u.Add(u, v), p)
+      v.Set(halfBig(t, p))
+      qk.Mul(qk, qMod).Mod(qk, p)
+    }
+  }
+
+  if u.Sign() == 0 || v.Sign() == 0 {
+    return true
+  }
+  for r := uint(1); r < s; r++ {
+    // V_2k = V_k² - 2Q^k
+    v.Mul(v, v).Sub(v, t.Lsh(qk, 1)).Mod(v, p)
+    if v.Sign() == 0 {
+      return true
+    }
+    qk.Mul(qk, qk).Mod(qk, p)
+  }
+
+  return false
+}
+
+// jacobi64 calculates the Jacobi symbol (d/n) for odd d and odd n. In contrast to modular.Jacobi, n may be greater
+// than MaxInt64. It applies the law of quadratic reciprocity once, so that the remaining calculation fits into int64.
+func jacobi64(d int64, n uint64) int {
+  result := 1
+  if d < 0 {
+    d = -d
+    // (-1/n) = -1 if n ≡ 3 mod 4
+    if n%4 == 3 {
+      result = -result
+    }
+  }
+
+  // quadratic reciprocity: (d/n) = -(n/d) if d ≡ n ≡ 3 mod 4
+  if d%4 == 3 && n%4 == 3 {
+    result = -result
+  }
+  return result * modular.Jacobi(int64(n%uint64(d)), d)
+}
+
+// isSquare64 tests if n is a perfect square.
+func isSquare64(n uint64) bool {
+  // math.Sqrt might be off by one for large n because of rounding
+  r := uint64(math.Sqrt(float64(n)))
+  for r > math.MaxUint32 || r*r > n {
+    r--
+  }
+  for r < math.MaxUint32 && (r+1)*(r+1) <= n {
+    r++
+  }
+  return r*r == n
+}
+
+// abs64 returns the absolute value of x as uint64.
+func abs64(x int64) uint64 {
+  if x < 0 {
+    return uint64(-x)
+  }
+  return uint64(x)
+}
+
+// residue64 returns x mod p in the range [0, p) for a possibly negative x.
+func residue64(x int64, p uint64) uint64 {
+  if x < 0 {
+    return modular.SubMod64(0, uint64(-x), p)
+  }
+  return uint64(x) % p
+}
+
+// half64 calculates x/2 mod p for odd p and 0 <= x < p.
+func half64(x, p uint64) uint64 {
+  if x%2 == 0 {
+    return x / 2
+  }
+  // (x+p)/2 might overflow, both x and p are odd
+  return x>>1 + p>>1 + 1
+}
+
+// halfBig sets x to x/2 mod p for odd p and non-negative x and returns x.
+func halfBig(x, p *big.Int) *big.Int {
+  x.Mod(x, p)
+  if x.Bit(0) == 1 {
+    x.Add(x, p)
+  }
+  return x.Rsh(x, 1)
+}
@@ -0,0 +1,178 @@
+package prime_test
+
+import (
+  "math"
+  "math/big"
+  "math/rand"
+  "testing"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/prime"
+)
+
+var _ = Describe("IsPrimeBailliePSW64", func() {
+  It("should correctly detect primes", func() {
+    testPrimes1000(func(i int32) bool {
+      return prime.IsPrimeBailliePSW64(uint64(i))
+    })
+  })
+
+  It("should return the same results as the sieve of Eratosthenes", func() {
+    isPrime := sieve(1000000)
+    for p := range isPrime {
+      Expect(prime.IsPrimeBailliePSW64(uint64(p))).To(Equal(isPrime[p]), "%d", p)
+    }
+  })
+
+  It("should return the same results as IsPrime64", func() {
+    for p := uint64(math.MaxUint64); p > math.MaxUint64-2000; p-- {
+      Expect(prime.IsPrimeBailliePSW64(p)).To(Equal(prime.IsPrime64(p)), "%d", p)
+    }
+    for p := uint64(math.MaxUint32 - 1000); p < math.MaxUint32+1000; p++ {
+      Expect(prime.IsPrimeBailliePSW64(p)).To(Equal(prime.IsPrime64(p)), "%d", p)
+    }
+  })
+
+  It("should return the same results as big.Int.ProbablyPrime", func() {
+    rnd := rand.New(rand.NewSource(1))
+    for i := 0; i < 10000; i++ {
+      p := rnd.Uint64()
+      Expect(prime.IsPrimeBailliePSW64(p)).To(Equal(new(big.Int).SetUint64(p).ProbablyPrime(0)), "%d", p)
+    }
+  })
+
+  It("should detect pseudoprimes", func() {
+    test := func(p uint64) {
+      ExpectWithOffset(1, prime.IsPrimeBailliePSW64(p)).To(BeFalse(), "%d", p)
+    }
+
+    for _, pseudoprimes := range strongPseudoprimes {
+      for _, p := range pseudoprimes {
+        test(uint64(p))
+      }
+    }
+    for _, p := range carmichaelNumbers {
+      test(uint64(p))
+    }
+    for _, p := range strongLucasPseudoprimes {
+      test(p)
+    }
+    test(3215031751)
+    test(4759123141)
+    test(3825123056546413051)
+    test((1<<32 - 5) * (1<<32 - 17))
+    test((1<<32 - 5) * (1<<32 - 5))
+  })
+})
+
+var _ = Describe("IsPrimeBailliePSWBig", func() {
+  It("should correctly detect primes", func() {
+    testPrimes1000(func(i int32) bool {
+      return prime.IsPrimeBailliePSWBig(big.NewInt(int64(i)))
+    })
+    Expect(prime.IsPrimeBailliePSWBig(big.NewInt(-7))).To(BeFalse())
+  })
+
+  It("should return the same results as IsPrimeBailliePSW64", func() {
+    for p := uint64(math.MaxUint64); p > math.MaxUint64-2000; p-- {
+      Expect(prime.IsPrimeBailliePSWBig(new(big.Int).SetUint64(p))).To(Equal(prime.IsPrimeBailliePSW64(p)), "%d", p)
+    }
+  })
+
+  It("should return the same results as big.Int.ProbablyPrime", func() {
+    rnd := rand.New(rand.NewSource(1))
+    for _, bits := range []uint{64, 65, 128, 256} {
+      limit := new(big.Int).Lsh(big.NewInt(1), bits)
+      for i := 0; i < 1000; i++ {
+        p := new(big.Int).Rand(rnd, limit)
+        Expect(prime.IsPrimeBailliePSWBig(p)).To(Equal(p.ProbablyPrime(0)), "%s", p)
+      }
+    }
+  })
+
+  It("should correctly detect Mersenne primes", func() {
+    test := func(exp uint, expected bool) {
+      p := new(big.Int).Lsh(big.NewInt(1), exp)
+      p.Sub(p, big.NewInt(1))
+      ExpectWithOffset(1, prime.IsPrimeBailliePSWBig(p)).To(Equal(expected), "2^%d - 1", exp)
+    }
+
+    test(61, true)
+    test(89, true)
+    test(107, true)
+    test(127, true)
+    test(521, true)
+    test(67, false)
+    test(101, false)
+    test(128, false)
+  })
+
+  It("should detect products of large primes", func() {
+    // (2^61 - 1) * (2^89 - 1)
+    p := new(big.Int).Mul(new(big.Int).SetUint64(1<<61-1), new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 89),
+      big.NewInt(1)))
+    Expect(prime.IsPrimeBailliePSWBig(p)).To(BeFalse())
+    Expect(prime.IsPrimeBailliePSWBig(new(big.Int).Mul(p, p))).To(BeFalse())
+  })
+})
+
+var _ = Describe("IsStrongLucasProbablePrime64", func() {
+  It("should return true for all primes", func() {
+    for _, p := range primes1000 {
+      Expect(prime.IsStrongLucasProbablePrime64(uint64(p))).To(BeTrue(), "%d", p)
+      Expect(prime.IsStrongLucasProbablePrimeBig(big.NewInt(int64(p)))).To(BeTrue(), "%d", p)
+    }
+  })
+
+  It("should return true for strong Lucas pseudoprimes", func() {
+    for _, p := range strongLucasPseudoprimes {
+      Expect(prime.IsStrongLucasProbablePrime64(p)).To(BeTrue(), "%d", p)
+      Expect(prime.IsStrongLucasProbablePrimeBig(new(big.Int).SetUint64(p))).To(BeTrue(), "%d", p)
+      // strong Lucas pseudoprimes are detected by the strong probable prime test to base 2
+      Expect(prime.IsStrongProbablePrime64(p, 2)).To(BeFalse(), "%d", p)
+    }
+  })
+
+  It("should return false for composites", func() {
+    test := func(p uint64) {
+      ExpectWithOffset(1, prime.IsStrongLucasProbablePrime64(p)).To(BeFalse(), "%d", p)
+      ExpectWithOffset(1, prime.IsStrongLucasProbablePrimeBig(new(big.Int).SetUint64(p))).To(BeFalse(), "%d", p)
+    }
+
+    test(0)
+    test(1)
+    test(4)
+    test(15)
+    test(2047)
+    test(3825123056546413051)
+    test(math.MaxUint64)
+    // perfect squares
+    test(9)
+    test(25)
+    test((1<<32 - 5) * (1<<32 - 5))
+    test((1<<32 - 1) * (1<<32 - 1))
+  })
+
+  It("should not be fooled by strong pseudoprimes", func() {
+    for _, pseudoprimes := range strongPseudoprimes {
+      for _, p := range pseudoprimes {
+        Expect(prime.IsStrongLucasProbablePrime64(uint64(p))).To(BeFalse(), "%d", p)
+      }
+    }
+  })
+})
+
+func BenchmarkIsPrimeBailliePSW64(b *testing.B) {
+  for i := 0; i < b.N; i++ {
+    prime.IsPrimeBailliePSW64(1<<64 - 59)
+  }
+}
+
+func BenchmarkIsPrimeBailliePSWBig(b *testing.B) {
+  p := new(big.Int).SetUint64(1<<64 - 59)
+  for i := 0; i < b.N; i++ {
+    prime.IsPrimeBailliePSWBig(p)
+  }
+}
@@ -1,8 +1,7 @@
 package prime
 
 import (
-  "math/rand"
-
+  "github.com/timebertt/grypto/internal/random"
   "github.com/timebertt/grypto/modular"
 )
 
@@ -18,6 +17,11 @@ import (
 // for which we can at least tell the probability of error.
 // See: https://en.wikipedia.org/wiki/Fermat_primality_test
 func IsPrimeFermat32(p int32, rounds int) (isPrime bool) {
+  return IsPrimeFermatWithOptions32(p, Options{Rounds: rounds})
+}
+
+// IsPrimeFermatWithOptions32 is like IsPrimeFermat32 but is configured by the given Options.
+func IsPrimeFermatWithOptions32(p int32, opts Options) (isPrime bool) {
   if p <= 1 {
     // 1 is neither prime nor composite
     return false
@@ -32,17 +36,45 @@ func IsPrimeFermat32(p int32, rounds int) (isPrime bool) {
     return false
   }
 
-  for r := 0; r < rounds; r++ {
+  for r := 0; r < opts.Rounds; r++ {
     // choose random integer in range [2,n-2]
-    a := rand.Int31n(p-3) + 2
+    a := random.Int31n(opts.Rand, p-3) + 2
 
     // a^(p-1) ≡ 1 mod p must be true if p is prime
-    if modular.Pow32(a, p-1, p) != 1 {
+    if x := modular.Pow32(a, p-1, p); x != 1 {
       // p is definitely not prime
+      if opts.Trace != nil {
+        opts.Trace("round %d: %d^%d ≡ %d mod %d => %d is a Fermat witness, %d is composite",
+          r+1, a, p-1, x, p, a, p)
+      }
       return false
     }
+    if opts.Trace != nil {
+      opts.Trace("round %d: %d^%d ≡ 1 mod %d => %d is a Fermat liar, if %d is composite", r+1, a, p-1, p, a, p)
+    }
   }
 
   // p is pseudo prime, we can't be sure if p is really prime
   return true
 }
+
+// IsFermatProbablePrime32 tests if p is a Fermat probable prime to base a, i.e. if a^(p-1) ≡ 1 mod p and a is not a
+// Fermat witness against p's primality. All primes are Fermat probable primes to all bases. Composite numbers that
+// are Fermat probable primes to base a are called Fermat pseudoprimes to base a (e.g. 341 to base 2). Carmichael
+// numbers are Fermat pseudoprimes to all bases coprime to them.
+// If a is a multiple of p, it can't be used as a witness and IsFermatProbablePrime32 returns true.
+func IsFermatProbablePrime32(p, a int32) bool {
+  if p <= 1 {
+    return false
+  }
+
+  a %= p
+  if a < 0 {
+    a += p
+  }
+  if a == 0 {
+    return true
+  }
+
+  return modular.Pow32(a, p-1, p) == 1
+}
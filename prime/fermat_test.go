@@ -1,20 +1,73 @@
 package prime_test
 
 import (
+  "fmt"
   "math"
+  "math/rand"
   "testing"
 
   . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
 
   "github.com/timebertt/grypto/prime"
 )
 
 var _ = Describe("IsPrimeFermat32", func() {
   It("should correctly detect primes", func() {
+    // use a fixed seed, as Fermat's primality test reports Carmichael numbers (e.g. 1729) as prime with a high
+    // probability for an unlucky choice of random integers
+    rnd := rand.New(rand.NewSource(1))
     testPrimes1000(func(i int32) bool {
-      return prime.IsPrimeFermat32(i, 12)
+      return prime.IsPrimeFermatWithOptions32(i, prime.Options{Rounds: 12, Rand: rnd})
     })
   })
+
+  It("should return false for a fixed sequence with a Fermat witness", func() {
+    var lines []string
+    trace := func(format string, a ...interface{}) {
+      lines = append(lines, fmt.Sprintf(format, a...))
+    }
+
+    // 561 = 3 * 11 * 17 is a Carmichael number, so all integers coprime to 561 are Fermat liars
+    Expect(prime.IsPrimeFermatWithOptions32(561, prime.Options{Rounds: 5, Rand: &fixedReader{48, 1}, Trace: trace})).
+      To(BeFalse())
+    Expect(lines).To(Equal([]string{
+      "round 1: 50^560 ≡ 1 mod 561 => 50 is a Fermat liar, if 561 is composite",
+      "round 2: 3^560 ≡ 375 mod 561 => 3 is a Fermat witness, 561 is composite",
+    }))
+  })
+
+  It("should replay the same sequence for the same seed", func() {
+    run := func() []string {
+      var lines []string
+      trace := func(format string, a ...interface{}) {
+        lines = append(lines, fmt.Sprintf(format, a...))
+      }
+      opts := prime.Options{Rounds: 12, Rand: rand.New(rand.NewSource(42)), Trace: trace}
+      prime.IsPrimeFermatWithOptions32(2821, opts)
+      return lines
+    }
+
+    Expect(run()).To(Equal(run()))
+  })
+
+  It("should trace each round", func() {
+    var lines []string
+    trace := func(format string, a ...interface{}) {
+      lines = append(lines, fmt.Sprintf(format, a...))
+    }
+
+    Expect(prime.IsPrimeFermatWithOptions32(13, prime.Options{Rounds: 5, Trace: trace})).To(BeTrue())
+    Expect(lines).To(HaveLen(5))
+    for _, line := range lines {
+      Expect(line).To(MatchRegexp(`^round [1-5]: \d+\^12 ≡ 1 mod 13 => \d+ is a Fermat liar, if 13 is composite$`))
+    }
+
+    lines = nil
+    Expect(prime.IsPrimeFermatWithOptions32(1000001, prime.Options{Rounds: 5, Trace: trace})).To(BeFalse())
+    Expect(lines[len(lines)-1]).To(MatchRegexp(
+      `^round [1-5]: (\d+)\^1000000 ≡ \d+ mod 1000001 => (\d+) is a Fermat witness, 1000001 is composite$`))
+  })
 })
 
 func BenchmarkIsPrimeFermat32(b *testing.B) {
@@ -1,14 +1,101 @@
 package prime
 
 import (
-  "math/rand"
+  "math/big"
 
+  "github.com/timebertt/grypto/internal/random"
   "github.com/timebertt/grypto/modular"
 )
 
-// IsPrime32 tests p for primality using the default test (Miller-Rabin with 12 rounds).
+var (
+  // millerRabinBases32 are sufficient for testing all int32 numbers deterministically: the smallest strong pseudoprime
+  // to all of these bases is 4759123141 > MaxInt32.
+  millerRabinBases32 = []int32{2, 7, 61}
+  // millerRabinBases64 are sufficient for testing all uint64 numbers deterministically: the smallest strong
+  // pseudoprime to all of these bases is 318665857834031151167461 > MaxUint64.
+  millerRabinBases64 = []uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37}
+)
+
+// IsPrime32 tests p for primality using the default test (deterministic Miller-Rabin).
 func IsPrime32(p int32) bool {
-  return IsPrimeMillerRabin32(p, 12)
+  return IsPrimeMillerRabinDeterministic32(p)
+}
+
+// IsPrime64 tests p for primality using the default test (deterministic Miller-Rabin).
+func IsPrime64(p uint64) bool {
+  return IsPrimeMillerRabinDeterministic64(p)
+}
+
+// IsPrimeMillerRabinDeterministic32 tests p for primality using the deterministic variant of the Miller-Rabin
+// primality test. Instead of choosing random integers, it tests a fixed set of bases (2, 7 and 61), for which it is
+// known that there is no strong pseudoprime to all of them in the int32 range. Hence, the result is exact.
+// See: https://en.wikipedia.org/wiki/Miller-Rabin_primality_test#Testing_against_small_sets_of_bases
+func IsPrimeMillerRabinDeterministic32(p int32) bool {
+  for _, a := range millerRabinBases32 {
+    if p == a {
+      return true
+    }
+    if !IsStrongProbablePrime32(p, a) {
+      return false
+    }
+  }
+  return true
+}
+
+// IsPrimeMillerRabinDeterministic64 is like IsPrimeMillerRabinDeterministic32 but for uint64 numbers. It tests the
+// first 12 primes (2, 3, ..., 37) as bases.
+func IsPrimeMillerRabinDeterministic64(p uint64) bool {
+  for _, a := range millerRabinBases64 {
+    if p == a {
+      return true
+    }
+    if !IsStrongProbablePrime64(p, a) {
+      return false
+    }
+  }
+  return true
+}
+
+// IsStrongProbablePrime32 tests if p is a strong probable prime to base a, i.e. if a is not a witness against p's
+// primality in the Miller-Rabin primality test. All primes are strong probable primes to all bases. Composite numbers
+// that are strong probable primes to base a are called strong pseudoprimes to base a (e.g. 2047 to base 2).
+// If a is a multiple of p, it can't be used as a witness and IsStrongProbablePrime32 returns true.
+func IsStrongProbablePrime32(p, a int32) bool {
+  if p <= 1 {
+    return false
+  }
+  if p%2 == 0 {
+    return p == 2
+  }
+
+  a %= p
+  if a < 0 {
+    a += p
+  }
+  if a == 0 {
+    return true
+  }
+
+  s, d := decompose32(p)
+  return isStrongProbablePrime32(p, a, s, d, &sequenceTrace{})
+}
+
+// IsStrongProbablePrime64 is like IsStrongProbablePrime32 but for uint64 numbers.
+func IsStrongProbablePrime64(p, a uint64) bool {
+  if p <= 1 {
+    return false
+  }
+  if p%2 == 0 {
+    return p == 2
+  }
+
+  a %= p
+  if a == 0 {
+    return true
+  }
+
+  s, d := decompose64(p)
+  return isStrongProbablePrime64(p, a, s, d, &sequenceTrace{})
 }
 
 // IsPrimeMillerRabin32 tests p for primality using the Miller-Rabin primality test.
@@ -22,6 +109,11 @@ func IsPrime32(p int32) bool {
 // If p is composite, then doing m rounds will report p as "probably prime" with a probability of 4^-m.
 // See: https://en.wikipedia.org/wiki/Miller-Rabin_primality_test
 func IsPrimeMillerRabin32(p int32, rounds int) (isPrime bool) {
+  return IsPrimeMillerRabinWithOptions32(p, Options{Rounds: rounds})
+}
+
+// IsPrimeMillerRabinWithOptions32 is like IsPrimeMillerRabin32 but is configured by the given Options.
+func IsPrimeMillerRabinWithOptions32(p int32, opts Options) (isPrime bool) {
   if p <= 1 {
     // 1 is neither prime nor composite
     return false
@@ -36,38 +128,163 @@ func IsPrimeMillerRabin32(p int32, rounds int) (isPrime bool) {
     return false
   }
 
-  // write p-1 as 2^s * d with d odd, by factoring out powers of 2
+  s, d := decompose32(p)
+  if opts.Trace != nil {
+    opts.Trace("%d - 1 = 2^%d * %d", p, s, d)
+  }
+
+  for r := 0; r < opts.Rounds; r++ {
+    // choose random integer in range [2,n-2]
+    a := random.Int31n(opts.Rand, p-3) + 2
+    seq := newSequenceTrace(opts.Trace, r, uint64(a), uint64(p))
+
+    if !isStrongProbablePrime32(p, a, s, d, &seq) {
+      // a is witness against p's primality, p is definitely composite
+      seq.witness()
+      return false
+    }
+    // a is not a witness against p's primality, choose next a
+    seq.liar()
+  }
+
+  // p is probably prime, for all chosen integers
+  return true
+}
+
+// IsStrongProbablePrimeBig is like IsStrongProbablePrime32 but for arbitrarily large integers.
+func IsStrongProbablePrimeBig(p, a *big.Int) bool {
   var (
-    s = int32(0)
-    d = p - 1
+    one = big.NewInt(1)
+    two = big.NewInt(2)
   )
+
+  if p.Cmp(two) < 0 {
+    return false
+  }
+  if p.Bit(0) == 0 {
+    return p.Cmp(two) == 0
+  }
+
+  // Mod also normalizes negative bases
+  a = new(big.Int).Mod(a, p)
+  if a.Sign() == 0 {
+    return true
+  }
+
+  // write p-1 as 2^s * d with d odd
+  pMinus1 := new(big.Int).Sub(p, one)
+  s := pMinus1.TrailingZeroBits()
+  d := new(big.Int).Rsh(pMinus1, s)
+
+  ad := modular.PowBig(a, d, p)
+  if ad.Cmp(one) == 0 || ad.Cmp(pMinus1) == 0 {
+    return true
+  }
+  for r := uint(1); r < s; r++ {
+    ad.Mul(ad, ad).Mod(ad, p)
+    if ad.Cmp(pMinus1) == 0 {
+      return true
+    }
+  }
+  return false
+}
+
+// decompose32 writes p-1 as 2^s * d with d odd, by factoring out powers of 2.
+func decompose32(p int32) (s, d int32) {
+  d = p - 1
   for d%2 == 0 {
     s++
     d /= 2
   }
+  return s, d
+}
 
-outer:
-  for r := 0; r < rounds; r++ {
-    // choose random integer in range [2,n-2]
-    a := rand.Int31n(p-3) + 2
-
-    ad := modular.Pow32(a, d, p)
-    if ad == 1 || ad == p-1 {
-      // a is not a witness against p's primality, choose next a
-      continue
-    }
-    for r := int32(0); r < s; r++ {
-      ad = modular.Pow32(ad, 2, p)
-      if ad == p-1 {
-        // a is not a witness against p's primality, choose next a
-        continue outer
-      }
+// isStrongProbablePrime32 tests if a^d ≡ 1 mod p or a^(2^r * d) ≡ -1 mod p for some r < s and adds all calculated
+// powers to seq.
+func isStrongProbablePrime32(p, a, s, d int32, seq *sequenceTrace) bool {
+  ad := modular.Pow32(a, d, p)
+  seq.add(uint64(ad))
+  if ad == 1 || ad == p-1 {
+    return true
+  }
+  for r := int32(1); r < s; r++ {
+    ad = modular.Pow32(ad, 2, p)
+    seq.add(uint64(ad))
+    if ad == p-1 {
+      return true
     }
+  }
+  return false
+}
 
-    // a is witness against p's primality, p is definitely composite
+// IsPrimeMillerRabin64 is like IsPrimeMillerRabin32 but for uint64 numbers. It uses modular.Pow64 and
+// modular.MulMod64 to avoid overflows of the intermediate products.
+func IsPrimeMillerRabin64(p uint64, rounds int) (isPrime bool) {
+  return IsPrimeMillerRabinWithOptions64(p, Options{Rounds: rounds})
+}
+
+// IsPrimeMillerRabinWithOptions64 is like IsPrimeMillerRabin64 but is configured by the given Options.
+func IsPrimeMillerRabinWithOptions64(p uint64, opts Options) (isPrime bool) {
+  if p <= 1 {
+    // 1 is neither prime nor composite
     return false
   }
 
+  // handle simple cases
+  if p <= 3 {
+    return true
+  }
+  if p%2 == 0 {
+    // if p is even we can directly say, that p is not prime
+    return false
+  }
+
+  s, d := decompose64(p)
+  if opts.Trace != nil {
+    opts.Trace("%d - 1 = 2^%d * %d", p, s, d)
+  }
+
+  for r := 0; r < opts.Rounds; r++ {
+    // choose random integer in range [2,n-2]
+    a := random.Uint64n(opts.Rand, p-3) + 2
+    seq := newSequenceTrace(opts.Trace, r, a, p)
+
+    if !isStrongProbablePrime64(p, a, s, d, &seq) {
+      // a is witness against p's primality, p is definitely composite
+      seq.witness()
+      return false
+    }
+    // a is not a witness against p's primality, choose next a
+    seq.liar()
+  }
+
   // p is probably prime, for all chosen integers
   return true
 }
+
+// decompose64 is like decompose32 but for uint64 numbers.
+func decompose64(p uint64) (s int, d uint64) {
+  d = p - 1
+  for d%2 == 0 {
+    s++
+    d /= 2
+  }
+  return s, d
+}
+
+// isStrongProbablePrime64 is like isStrongProbablePrime32 but for uint64 numbers.
+func isStrongProbablePrime64(p, a uint64, s int, d uint64, seq *sequenceTrace) bool {
+  ad := modular.Pow64(a, d, p)
+  seq.add(ad)
+  if ad == 1 || ad == p-1 {
+    return true
+  }
+  for r := 1; r < s; r++ {
+    ad = modular.MulMod64(ad, ad, p)
+    seq.add(ad)
+    if ad == p-1 {
+      return true
+    }
+  }
+  return false
+}
@@ -1,10 +1,14 @@
 package prime_test
 
 import (
+  "fmt"
   "math"
+  "math/big"
+  "math/rand"
   "testing"
 
   . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
 
   "github.com/timebertt/grypto/prime"
 )
@@ -15,6 +19,217 @@ var _ = Describe("IsPrimeMillerRabin32", func() {
       return prime.IsPrimeMillerRabin32(i, 12)
     })
   })
+
+  It("should return false for a fixed sequence with a witness", func() {
+    var lines []string
+    trace := func(format string, a ...interface{}) {
+      lines = append(lines, fmt.Sprintf(format, a...))
+    }
+
+    opts := prime.Options{Rounds: 5, Rand: &fixedReader{48, 0}, Trace: trace}
+    Expect(prime.IsPrimeMillerRabinWithOptions32(561, opts)).To(BeFalse())
+    Expect(lines).To(Equal([]string{
+      "561 - 1 = 2^4 * 35",
+      "round 1: a = 50, a^d, a^(2d), ... ≡ 560 mod 561 => 50 is a strong liar, if 561 is composite",
+      "round 2: a = 2, a^d, a^(2d), ... ≡ 263, 166, 67, 1 mod 561 => 2 is a witness, 561 is composite",
+    }))
+  })
+
+  It("should replay the same sequence for the same seed", func() {
+    run := func() []string {
+      var lines []string
+      trace := func(format string, a ...interface{}) {
+        lines = append(lines, fmt.Sprintf(format, a...))
+      }
+      opts := prime.Options{Rounds: 12, Rand: rand.New(rand.NewSource(42)), Trace: trace}
+      prime.IsPrimeMillerRabinWithOptions32(2147483647, opts)
+      return lines
+    }
+
+    Expect(run()).To(HaveLen(13))
+    Expect(run()).To(Equal(run()))
+  })
+
+  It("should trace each round", func() {
+    var lines []string
+    trace := func(format string, a ...interface{}) {
+      lines = append(lines, fmt.Sprintf(format, a...))
+    }
+
+    Expect(prime.IsPrimeMillerRabinWithOptions32(13, prime.Options{Rounds: 5, Trace: trace})).To(BeTrue())
+    Expect(lines).To(HaveLen(6))
+    Expect(lines[0]).To(Equal("13 - 1 = 2^2 * 3"))
+    for _, line := range lines[1:] {
+      Expect(line).To(MatchRegexp(`^round [1-5]: a = \d+, a\^d, a\^\(2d\), ... ≡ (1|12|\d+, 12) mod 13 => ` +
+        `\d+ is a strong liar, if 13 is composite$`))
+    }
+
+    lines = nil
+    Expect(prime.IsPrimeMillerRabinWithOptions32(561, prime.Options{Rounds: 12, Trace: trace})).To(BeFalse())
+    Expect(lines[0]).To(Equal("561 - 1 = 2^4 * 35"))
+    Expect(lines[len(lines)-1]).To(MatchRegexp(
+      `^round \d+: a = \d+, a\^d, a\^\(2d\), ... ≡ (\d+, ){3}\d+ mod 561 => \d+ is a witness, 561 is composite$`))
+  })
+})
+
+var _ = Describe("IsPrimeMillerRabin64", func() {
+  It("should correctly detect primes", func() {
+    testPrimes1000(func(i int32) bool {
+      return prime.IsPrimeMillerRabin64(uint64(i), 12)
+    })
+  })
+
+  It("should return the same results as IsPrimeMillerRabin32", func() {
+    for p := int32(math.MaxInt32); p > math.MaxInt32-2000; p-- {
+      Expect(prime.IsPrimeMillerRabin64(uint64(p), 12)).To(Equal(prime.IsPrimeMillerRabin32(p, 12)), "%d", p)
+    }
+  })
+
+  It("should use the given source of randomness", func() {
+    var lines []string
+    trace := func(format string, a ...interface{}) {
+      lines = append(lines, fmt.Sprintf(format, a...))
+    }
+
+    opts := prime.Options{Rounds: 5, Rand: &fixedReader{48, 0}, Trace: trace}
+    Expect(prime.IsPrimeMillerRabinWithOptions64(561, opts)).To(BeFalse())
+    Expect(lines).To(Equal([]string{
+      "561 - 1 = 2^4 * 35",
+      "round 1: a = 50, a^d, a^(2d), ... ≡ 560 mod 561 => 50 is a strong liar, if 561 is composite",
+      "round 2: a = 2, a^d, a^(2d), ... ≡ 263, 166, 67, 1 mod 561 => 2 is a witness, 561 is composite",
+    }))
+
+    Expect(func() {
+      prime.IsPrimeMillerRabinWithOptions64(1<<61-1, prime.Options{Rounds: 5, Rand: &fixedReader{1}})
+    }).To(Panic())
+  })
+
+  It("should correctly detect 64-bit primes", func() {
+    test := func(p uint64, expected bool) {
+      ExpectWithOffset(1, prime.IsPrimeMillerRabin64(p, 12)).To(Equal(expected), "%d", p)
+      ExpectWithOffset(1, prime.IsPrime64(p)).To(Equal(expected), "%d", p)
+    }
+
+    test(1<<61-1, true)
+    test(1<<64-59, true)
+    test(1<<63-25, true)
+    test(1<<64-1, false)
+    test(1<<63, false)
+    // 4294967291 and 4294967279 are the two largest primes below 2^32
+    test(4294967291*4294967279, false)
+    // product of two primes close to 2^32
+    test((1<<32-5)*(1<<32-17), false)
+  })
+})
+
+var _ = Describe("IsStrongProbablePrime32", func() {
+  It("should return true for all primes", func() {
+    for _, p := range primes1000 {
+      for _, a := range []int32{2, 3, 5, 7, 61, 1000} {
+        Expect(prime.IsStrongProbablePrime32(p, a)).To(BeTrue(), "%d to base %d", p, a)
+      }
+    }
+  })
+
+  It("should return true for strong pseudoprimes", func() {
+    for a, pseudoprimes := range strongPseudoprimes {
+      for _, p := range pseudoprimes {
+        Expect(prime.IsStrongProbablePrime32(p, a)).To(BeTrue(), "%d to base %d", p, a)
+        Expect(prime.IsStrongProbablePrime64(uint64(p), uint64(a))).To(BeTrue(), "%d to base %d", p, a)
+        Expect(prime.IsStrongProbablePrimeBig(big.NewInt(int64(p)), big.NewInt(int64(a)))).To(BeTrue(),
+          "%d to base %d", p, a)
+        Expect(prime.IsPrime32(p)).To(BeFalse(), "%d", p)
+      }
+    }
+  })
+
+  It("should return false for composites with witness a", func() {
+    Expect(prime.IsStrongProbablePrime32(561, 2)).To(BeFalse())
+    Expect(prime.IsStrongProbablePrime32(2047, 3)).To(BeFalse())
+    Expect(prime.IsStrongProbablePrime32(9, 2)).To(BeFalse())
+    Expect(prime.IsStrongProbablePrime32(0, 2)).To(BeFalse())
+    Expect(prime.IsStrongProbablePrime32(1, 2)).To(BeFalse())
+    Expect(prime.IsStrongProbablePrime32(4, 3)).To(BeFalse())
+    Expect(prime.IsStrongProbablePrimeBig(big.NewInt(561), big.NewInt(2))).To(BeFalse())
+    Expect(prime.IsStrongProbablePrimeBig(big.NewInt(1), big.NewInt(2))).To(BeFalse())
+  })
+
+  It("should return true for multiples of p as base", func() {
+    Expect(prime.IsStrongProbablePrime32(7, 7)).To(BeTrue())
+    Expect(prime.IsStrongProbablePrime32(61, 122)).To(BeTrue())
+    Expect(prime.IsStrongProbablePrime32(2, 2)).To(BeTrue())
+    Expect(prime.IsStrongProbablePrime64(7, 0)).To(BeTrue())
+  })
+
+  It("should normalize negative bases", func() {
+    Expect(prime.IsStrongProbablePrime32(2047, 2-2047)).To(BeTrue())
+    Expect(prime.IsStrongProbablePrime32(561, -1)).To(BeTrue())
+    Expect(prime.IsStrongProbablePrime32(561, 2-561)).To(BeFalse())
+  })
+})
+
+var _ = Describe("IsPrimeMillerRabinDeterministic32", func() {
+  It("should correctly detect primes", func() {
+    testPrimes1000(prime.IsPrimeMillerRabinDeterministic32)
+    testPrimes1000(prime.IsPrime32)
+  })
+
+  It("should return the same results as the sieve of Eratosthenes", func() {
+    isPrime := sieve(1000000)
+    for p := range isPrime {
+      Expect(prime.IsPrimeMillerRabinDeterministic32(int32(p))).To(Equal(isPrime[p]), "%d", p)
+    }
+  })
+
+  It("should detect strong pseudoprimes to multiple bases", func() {
+    // 1373653 is the smallest strong pseudoprime to bases 2 and 3
+    Expect(prime.IsStrongProbablePrime32(1373653, 2)).To(BeTrue())
+    Expect(prime.IsStrongProbablePrime32(1373653, 3)).To(BeTrue())
+    Expect(prime.IsPrime32(1373653)).To(BeFalse())
+    // 25326001 is the smallest strong pseudoprime to bases 2, 3 and 5
+    Expect(prime.IsStrongProbablePrime32(25326001, 2)).To(BeTrue())
+    Expect(prime.IsStrongProbablePrime32(25326001, 3)).To(BeTrue())
+    Expect(prime.IsStrongProbablePrime32(25326001, 5)).To(BeTrue())
+    Expect(prime.IsPrime32(25326001)).To(BeFalse())
+  })
+
+  It("should return the same results as IsPrimeMillerRabin32", func() {
+    for p := int32(math.MaxInt32); p > math.MaxInt32-2000; p-- {
+      Expect(prime.IsPrimeMillerRabinDeterministic32(p)).To(Equal(prime.IsPrimeMillerRabin32(p, 12)), "%d", p)
+    }
+  })
+})
+
+var _ = Describe("IsPrimeMillerRabinDeterministic64", func() {
+  It("should correctly detect primes", func() {
+    testPrimes1000(func(i int32) bool {
+      return prime.IsPrimeMillerRabinDeterministic64(uint64(i))
+    })
+  })
+
+  It("should return the same results as IsPrimeMillerRabinDeterministic32", func() {
+    for p := int32(math.MaxInt32); p > math.MaxInt32-2000; p-- {
+      Expect(prime.IsPrimeMillerRabinDeterministic64(uint64(p))).To(Equal(prime.IsPrimeMillerRabinDeterministic32(p)),
+        "%d", p)
+    }
+  })
+
+  It("should detect strong pseudoprimes to multiple bases", func() {
+    test := func(p uint64, bases ...uint64) {
+      for _, a := range bases {
+        ExpectWithOffset(1, prime.IsStrongProbablePrime64(p, a)).To(BeTrue(), "%d to base %d", p, a)
+      }
+      ExpectWithOffset(1, prime.IsPrime64(p)).To(BeFalse(), "%d", p)
+    }
+
+    // 3215031751 is the smallest strong pseudoprime to bases 2, 3, 5 and 7
+    test(3215031751, 2, 3, 5, 7)
+    // 4759123141 is the smallest strong pseudoprime to bases 2, 7 and 61, i.e. it would fool IsPrime32
+    test(4759123141, 2, 7, 61)
+    // 3825123056546413051 is a strong pseudoprime to all prime bases up to 31
+    test(3825123056546413051, 2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31)
+    Expect(prime.IsStrongProbablePrime64(3825123056546413051, 37)).To(BeFalse())
+  })
 })
 
 func BenchmarkIsPrimeMillerRabin32(b *testing.B) {
@@ -22,3 +237,21 @@ func BenchmarkIsPrimeMillerRabin32(b *testing.B) {
     prime.IsPrimeMillerRabin32(math.MaxInt32, 12)
   }
 }
+
+func BenchmarkIsPrimeMillerRabin64(b *testing.B) {
+  for i := 0; i < b.N; i++ {
+    prime.IsPrimeMillerRabin64(1<<64-59, 12)
+  }
+}
+
+func BenchmarkIsPrimeMillerRabinDeterministic32(b *testing.B) {
+  for i := 0; i < b.N; i++ {
+    prime.IsPrimeMillerRabinDeterministic32(math.MaxInt32)
+  }
+}
+
+func BenchmarkIsPrimeMillerRabinDeterministic64(b *testing.B) {
+  for i := 0; i < b.N; i++ {
+    prime.IsPrimeMillerRabinDeterministic64(1<<64 - 59)
+  }
+}
@@ -0,0 +1,19 @@
+package prime
+
+import (
+  "io"
+
+  "github.com/timebertt/grypto/modular"
+)
+
+// Options configures the probabilistic primality tests.
+type Options struct {
+  // Rounds is the number of random integers tested.
+  Rounds int
+  // Rand is the source of randomness used for choosing the random integers. It can be set to crypto/rand.Reader for
+  // cryptographically secure randomness or to a *math/rand.Rand with a fixed seed for reproducing the exact sequence
+  // of chosen integers. If Rand is nil, the global math/rand source is used.
+  Rand io.Reader
+  // Trace is called for each round with the chosen integer and whether it is a witness or liar, if it is not nil.
+  Trace modular.TraceFunc
+}
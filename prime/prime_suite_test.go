@@ -1,7 +1,9 @@
 package prime_test
 
 import (
+  "encoding/binary"
   "fmt"
+  "io"
   "testing"
 
   . "github.com/onsi/ginkgo"
@@ -13,6 +15,23 @@ func TestPrime(t *testing.T) {
   RunSpecs(t, "Prime Suite")
 }
 
+// fixedReader is an io.Reader returning the given uint64 values (big endian) for replaying exact sequences of random
+// integers.
+type fixedReader []uint64
+
+func (r *fixedReader) Read(p []byte) (int, error) {
+  if len(*r) == 0 {
+    return 0, io.EOF
+  }
+  if len(p) < 8 {
+    return 0, io.ErrShortBuffer
+  }
+
+  binary.BigEndian.PutUint64(p, (*r)[0])
+  *r = (*r)[1:]
+  return 8, nil
+}
+
 func testPrimes1000(f func(int32) bool) {
   ExpectWithOffset(1, f(0)).To(BeFalse(), "0 is not prime")
   ExpectWithOffset(1, f(1)).To(BeFalse(), "1 is neither prime nor composite")
@@ -82,3 +101,42 @@ var primes1000 = []int32{
   7573, 7577, 7583, 7589, 7591, 7603, 7607, 7621, 7639, 7643, 7649, 7669, 7673, 7681, 7687, 7691, 7699, 7703, 7717, 7723,
   7727, 7741, 7753, 7757, 7759, 7789, 7793, 7817, 7823, 7829, 7841, 7853, 7867, 7873, 7877, 7879, 7883, 7901, 7907, 7919,
 }
+
+// sieve returns a table of length n, that is true for all primes smaller than n (sieve of Eratosthenes).
+func sieve(n int) []bool {
+  isPrime := make([]bool, n)
+  for i := 2; i < n; i++ {
+    isPrime[i] = true
+  }
+
+  for i := 2; i*i < n; i++ {
+    if !isPrime[i] {
+      continue
+    }
+    for j := i * i; j < n; j += i {
+      isPrime[j] = false
+    }
+  }
+  return isPrime
+}
+
+// strongPseudoprimes contains the smallest strong pseudoprimes to some bases, i.e. composite numbers that are strong
+// probable primes to the given base.
+// See https://oeis.org/A001262, https://oeis.org/A020229, https://oeis.org/A020231, https://oeis.org/A020235.
+var strongPseudoprimes = map[int32][]int32{
+  2:  {2047, 3277, 4033, 4681, 8321, 15841, 29341, 42799, 49141, 52633, 65281, 74665, 80581, 85489, 88357, 90751},
+  3:  {121, 703, 1891, 3281, 8401, 8911, 10585, 12403, 16531, 18721, 19345, 23521, 31621},
+  5:  {781, 1541, 5461, 5611, 7813, 13021, 14981, 15751, 24211, 25351, 29539},
+  7:  {25, 325, 703, 2101, 2353, 4525, 11041, 14089, 20197, 29857, 29891},
+  61: {15, 217, 341, 1261, 2701, 3661, 6541, 6697, 7613, 13213, 16213, 22177},
+}
+
+// carmichaelNumbers contains the smallest Carmichael numbers, i.e. composite numbers n, for which a^(n-1) ≡ 1 mod n for
+// all a coprime to n.
+// See https://oeis.org/A002997.
+var carmichaelNumbers = []int32{561, 1105, 1729, 2465, 2821, 6601, 8911}
+
+// strongLucasPseudoprimes contains the smallest strong Lucas pseudoprimes with parameters chosen by Selfridge's
+// method A.
+// See https://oeis.org/A217255.
+var strongLucasPseudoprimes = []uint64{5459, 5777, 10877, 16109, 18971, 22499, 24569, 25199, 40309, 58519, 75077, 97439}
@@ -0,0 +1,97 @@
+package prime
+
+import (
+  "github.com/timebertt/grypto/internal/random"
+  "github.com/timebertt/grypto/modular"
+)
+
+// IsPrimeSolovayStrassen32 tests p for primality using the Solovay-Strassen primality test.
+// It repeatedly chooses a random integer between 2 and p-2 and tests if a^((p-1)/2) ≡ (a/p) mod p (rounds times),
+// where (a/p) is the Jacobi symbol. If p is prime, the condition must be true (according to Euler's criterion).
+// That means, that if the condition is false, a is called an Euler witness, p is definitely not prime and the test
+// stops. Otherwise, a is called an Euler liar, if p is composite.
+// In contrast to Fermat's primality test, there are no composite numbers for which all integers are Euler liars: for
+// every composite p, at most half of all integers are Euler liars. So the error probability after k rounds is at most
+// 2^-k, even for Carmichael numbers. Every strong liar (Miller-Rabin) is also an Euler liar, so the Miller-Rabin
+// primality test should be preferred anyway.
+// See: https://en.wikipedia.org/wiki/Solovay-Strassen_primality_test
+func IsPrimeSolovayStrassen32(p int32, rounds int) bool {
+  return IsPrimeSolovayStrassenWithOptions32(p, Options{Rounds: rounds})
+}
+
+// IsPrimeSolovayStrassenWithOptions32 is like IsPrimeSolovayStrassen32 but is configured by the given Options.
+func IsPrimeSolovayStrassenWithOptions32(p int32, opts Options) bool {
+  if p <= 1 {
+    // 1 is neither prime nor composite
+    return false
+  }
+
+  // handle simple cases
+  if p <= 3 {
+    return true
+  }
+  if p%2 == 0 {
+    // if p is even we can directly say, that p is not prime
+    return false
+  }
+
+  for r := 0; r < opts.Rounds; r++ {
+    // choose random integer in range [2,n-2]
+    a := random.Int31n(opts.Rand, p-3) + 2
+
+    // a^((p-1)/2) ≡ (a/p) mod p must be true if p is prime
+    j := modular.Jacobi(int64(a), int64(p))
+    x := modular.Pow32(a, (p-1)/2, p)
+    if !eulerCriterion32(p, j, x) {
+      // p is definitely not prime
+      if opts.Trace != nil {
+        opts.Trace("round %d: (%d/%d) = %d, %d^%d ≡ %d mod %d => %d is an Euler witness, %d is composite",
+          r+1, a, p, j, a, (p-1)/2, x, p, a, p)
+      }
+      return false
+    }
+    if opts.Trace != nil {
+      opts.Trace("round %d: (%d/%d) = %d, %d^%d ≡ %d mod %d => %d is an Euler liar, if %d is composite",
+        r+1, a, p, j, a, (p-1)/2, x, p, a, p)
+    }
+  }
+
+  // p is probably prime, for all chosen integers
+  return true
+}
+
+// IsEulerProbablePrime32 tests if p is an Euler probable prime to base a, i.e. if a^((p-1)/2) ≡ (a/p) mod p and a is
+// not an Euler witness against p's primality in the Solovay-Strassen primality test. All odd primes are Euler
+// probable primes to all bases. Composite numbers that are Euler probable primes to base a are called Euler-Jacobi
+// pseudoprimes to base a (e.g. 561 to base 2).
+// If a is a multiple of p, it can't be used as a witness and IsEulerProbablePrime32 returns true.
+func IsEulerProbablePrime32(p, a int32) bool {
+  if p <= 1 {
+    return false
+  }
+  if p%2 == 0 {
+    return p == 2
+  }
+
+  a %= p
+  if a < 0 {
+    a += p
+  }
+  if a == 0 {
+    return true
+  }
+
+  return eulerCriterion32(p, modular.Jacobi(int64(a), int64(p)), modular.Pow32(a, (p-1)/2, p))
+}
+
+// eulerCriterion32 tests if x ≡ j mod p for the Jacobi symbol j = (a/p) and x = a^((p-1)/2) mod p.
+func eulerCriterion32(p int32, j int, x int32) bool {
+  switch j {
+  case 1:
+    return x == 1
+  case -1:
+    return x == p-1
+  }
+  // gcd(a, p) > 1, p is composite
+  return false
+}
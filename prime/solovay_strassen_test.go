@@ -0,0 +1,166 @@
+package prime_test
+
+import (
+  "fmt"
+  "math"
+  "math/rand"
+  "testing"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/euclid"
+  "github.com/timebertt/grypto/prime"
+)
+
+var _ = Describe("IsPrimeSolovayStrassen32", func() {
+  It("should correctly detect primes", func() {
+    testPrimes1000(func(i int32) bool {
+      return prime.IsPrimeSolovayStrassenWithOptions32(i, prime.Options{Rounds: 12, Rand: rand.New(rand.NewSource(1))})
+    })
+  })
+
+  It("should return false for a fixed sequence with an Euler witness", func() {
+    var lines []string
+    trace := func(format string, a ...interface{}) {
+      lines = append(lines, fmt.Sprintf(format, a...))
+    }
+
+    // 561 = 3 * 11 * 17 is a Carmichael number, but not all integers coprime to 561 are Euler liars
+    opts := prime.Options{Rounds: 5, Rand: &fixedReader{0, 3}, Trace: trace}
+    Expect(prime.IsPrimeSolovayStrassenWithOptions32(561, opts)).To(BeFalse())
+    Expect(lines).To(Equal([]string{
+      "round 1: (2/561) = 1, 2^280 ≡ 1 mod 561 => 2 is an Euler liar, if 561 is composite",
+      "round 2: (5/561) = 1, 5^280 ≡ 67 mod 561 => 5 is an Euler witness, 561 is composite",
+    }))
+  })
+
+  It("should replay the same sequence for the same seed", func() {
+    run := func() []string {
+      var lines []string
+      trace := func(format string, a ...interface{}) {
+        lines = append(lines, fmt.Sprintf(format, a...))
+      }
+      opts := prime.Options{Rounds: 12, Rand: rand.New(rand.NewSource(42)), Trace: trace}
+      prime.IsPrimeSolovayStrassenWithOptions32(2147483647, opts)
+      return lines
+    }
+
+    Expect(run()).To(HaveLen(12))
+    Expect(run()).To(Equal(run()))
+  })
+
+  It("should trace each round", func() {
+    var lines []string
+    trace := func(format string, a ...interface{}) {
+      lines = append(lines, fmt.Sprintf(format, a...))
+    }
+
+    Expect(prime.IsPrimeSolovayStrassenWithOptions32(13, prime.Options{Rounds: 5, Trace: trace})).To(BeTrue())
+    Expect(lines).To(HaveLen(5))
+    for _, line := range lines {
+      Expect(line).To(MatchRegexp(`^round [1-5]: \(\d+/13\) = -?1, \d+\^6 ≡ (1|12) mod 13 => \d+ is an ` +
+        `Euler liar, if 13 is composite$`))
+    }
+
+    lines = nil
+    Expect(prime.IsPrimeSolovayStrassenWithOptions32(1000001, prime.Options{Rounds: 5, Trace: trace})).To(BeFalse())
+    Expect(lines[len(lines)-1]).To(MatchRegexp(
+      `^round [1-5]: \(\d+/1000001\) = -?[01], \d+\^500000 ≡ \d+ mod 1000001 => \d+ is an Euler witness, ` +
+        `1000001 is composite$`))
+  })
+})
+
+var _ = Describe("IsEulerProbablePrime32", func() {
+  It("should return true for all odd primes", func() {
+    for _, p := range primes1000 {
+      for _, a := range []int32{2, 3, 5, 7, 61, 1000} {
+        Expect(prime.IsEulerProbablePrime32(p, a)).To(BeTrue(), "%d to base %d", p, a)
+      }
+    }
+  })
+
+  It("should return false for composites with Euler witness a", func() {
+    Expect(prime.IsEulerProbablePrime32(341, 2)).To(BeFalse())
+    Expect(prime.IsEulerProbablePrime32(561, 3)).To(BeFalse())
+    Expect(prime.IsEulerProbablePrime32(9, 2)).To(BeFalse())
+    Expect(prime.IsEulerProbablePrime32(4, 3)).To(BeFalse())
+    Expect(prime.IsEulerProbablePrime32(1, 2)).To(BeFalse())
+  })
+
+  It("should return true for multiples of p as base", func() {
+    Expect(prime.IsEulerProbablePrime32(7, 14)).To(BeTrue())
+    Expect(prime.IsEulerProbablePrime32(2, 2)).To(BeTrue())
+  })
+})
+
+var _ = Describe("Carmichael numbers", func() {
+  It("should fool Fermat's primality test for all bases coprime to n", func() {
+    for _, n := range carmichaelNumbers {
+      for a := int32(2); a < n-1; a++ {
+        if euclid.GreatestCommonDivisor(int(a), int(n)) == 1 {
+          Expect(prime.IsFermatProbablePrime32(n, a)).To(BeTrue(), "%d to base %d", n, a)
+        }
+      }
+
+      // choose the coprime bases 2 and 4 (fixedReader values are offset by 2)
+      Expect(prime.IsPrimeFermatWithOptions32(n, prime.Options{Rounds: 2, Rand: &fixedReader{0, 2}})).
+        To(BeTrue(), "%d", n)
+    }
+  })
+
+  It("should fool the Solovay-Strassen and Miller-Rabin tests for some bases only", func() {
+    for _, n := range carmichaelNumbers {
+      var phi, eulerLiars, strongLiars int
+      for a := int32(1); a < n; a++ {
+        if euclid.GreatestCommonDivisor(int(a), int(n)) != 1 {
+          continue
+        }
+        phi++
+
+        isEulerLiar, isStrongLiar := prime.IsEulerProbablePrime32(n, a), prime.IsStrongProbablePrime32(n, a)
+        if isEulerLiar {
+          eulerLiars++
+        }
+        if isStrongLiar {
+          strongLiars++
+          // every strong liar is also an Euler liar
+          Expect(isEulerLiar).To(BeTrue(), "%d to base %d", n, a)
+        }
+      }
+
+      // at most half of all bases coprime to n are Euler liars, at most a quarter are strong liars
+      Expect(eulerLiars).To(BeNumerically("<=", phi/2), "%d", n)
+      Expect(strongLiars).To(BeNumerically("<=", phi/4), "%d", n)
+
+      rnd := rand.New(rand.NewSource(1))
+      Expect(prime.IsPrimeSolovayStrassenWithOptions32(n, prime.Options{Rounds: 20, Rand: rnd})).To(BeFalse(), "%d", n)
+      Expect(prime.IsPrimeMillerRabinWithOptions32(n, prime.Options{Rounds: 20, Rand: rnd})).To(BeFalse(), "%d", n)
+    }
+  })
+
+  It("should show which test is fooled by a specific base", func() {
+    test := func(n, a int32, fermat, euler, strong bool) {
+      ExpectWithOffset(1, prime.IsFermatProbablePrime32(n, a)).To(Equal(fermat), "%d to base %d", n, a)
+      ExpectWithOffset(1, prime.IsEulerProbablePrime32(n, a)).To(Equal(euler), "%d to base %d", n, a)
+      ExpectWithOffset(1, prime.IsStrongProbablePrime32(n, a)).To(Equal(strong), "%d to base %d", n, a)
+    }
+
+    // 2 is a Fermat and Euler liar but a strong witness for 561
+    test(561, 2, true, true, false)
+    // 3 divides 561, so it is a witness for all tests
+    test(561, 3, false, false, false)
+    // 5 is a Fermat liar but an Euler witness for 561
+    test(561, 5, true, false, false)
+    // 50 is a strong liar for 561 and hence also an Euler liar
+    test(561, 50, true, true, true)
+    // 2 is a Fermat liar but an Euler witness for 2821
+    test(2821, 2, true, false, false)
+  })
+})
+
+func BenchmarkIsPrimeSolovayStrassen32(b *testing.B) {
+  for i := 0; i < b.N; i++ {
+    prime.IsPrimeSolovayStrassen32(math.MaxInt32, 12)
+  }
+}
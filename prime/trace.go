@@ -0,0 +1,47 @@
+package prime
+
+import (
+  "fmt"
+  "strings"
+
+  "github.com/timebertt/grypto/modular"
+)
+
+// sequenceTrace collects the sequence a^d, a^(2d), ... mod p of a single Miller-Rabin round for tracing it.
+// All methods are no-ops, if trace is nil. They check trace before passing any values as interface{} arguments, so
+// that they don't cause any allocations if tracing is disabled.
+type sequenceTrace struct {
+  trace    modular.TraceFunc
+  round    int
+  a, p     uint64
+  sequence []string
+}
+
+func newSequenceTrace(trace modular.TraceFunc, round int, a, p uint64) sequenceTrace {
+  return sequenceTrace{trace: trace, round: round, a: a, p: p}
+}
+
+func (s *sequenceTrace) add(x uint64) {
+  if s.trace != nil {
+    s.sequence = append(s.sequence, fmt.Sprint(x))
+  }
+}
+
+func (s *sequenceTrace) liar() {
+  if s.trace == nil {
+    return
+  }
+  s.printf("%d is a strong liar, if %d is composite", s.a, s.p)
+}
+
+func (s *sequenceTrace) witness() {
+  if s.trace == nil {
+    return
+  }
+  s.printf("%d is a witness, %d is composite", s.a, s.p)
+}
+
+func (s *sequenceTrace) printf(format string, a ...interface{}) {
+  s.trace("round %d: a = %d, a^d, a^(2d), ... ≡ %s mod %d => "+format,
+    append([]interface{}{s.round + 1, s.a, strings.Join(s.sequence, ", "), s.p}, a...)...)
+}
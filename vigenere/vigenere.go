@@ -0,0 +1,79 @@
+package vigenere
+
+import (
+  "crypto/cipher"
+  "errors"
+  "fmt"
+
+  "github.com/timebertt/grypto/caesar"
+)
+
+type vigenere struct {
+  // ciphers holds a Caesar Cipher for each letter of the key
+  ciphers []cipher.Block
+  // pos is the position in the key, that is used for the next latin character
+  pos     int
+  decrypt bool
+}
+
+// NewEncrypter returns a new cipher.Stream which encrypts using the Vigenère Cipher.
+// The Vigenère Cipher is a polyalphabetic substitution cipher, that is keyed by a word. Each latin character is
+// encrypted with the Caesar Cipher using the next letter of the key as the Caesar key (A=0, B=1, ..., Z=25).
+// Once all letters of the key are used, it starts again with the first letter. Like in the Caesar Cipher, the
+// characters' case is kept and non-latin characters are not replaced (and don't use up a letter of the key).
+// The key must only contain latin letters, the case of the key letters is ignored.
+// The stream keeps track of the position in the key, so subsequent calls to XORKeyStream continue where the
+// previous one stopped. Despite the name of the method, characters are substituted instead of XORed.
+// See https://en.wikipedia.org/wiki/Vigen%C3%A8re_cipher
+func NewEncrypter(key string) (cipher.Stream, error) {
+  return newVigenere(key, false)
+}
+
+// NewDecrypter returns a new cipher.Stream which decrypts using the Vigenère Cipher. See NewEncrypter.
+func NewDecrypter(key string) (cipher.Stream, error) {
+  return newVigenere(key, true)
+}
+
+func newVigenere(key string, decrypt bool) (*vigenere, error) {
+  if len(key) == 0 {
+    return nil, errors.New("grypto/vigenere: key must not be empty")
+  }
+
+  ciphers := make([]cipher.Block, len(key))
+  for i := 0; i < len(key); i++ {
+    switch k := key[i]; {
+    case k >= 'A' && k <= 'Z':
+      ciphers[i] = caesar.NewCipher(int(k - 'A'))
+    case k >= 'a' && k <= 'z':
+      ciphers[i] = caesar.NewCipher(int(k - 'a'))
+    default:
+      return nil, fmt.Errorf("grypto/vigenere: key must only contain latin letters, got %q", key)
+    }
+  }
+
+  return &vigenere{ciphers: ciphers, decrypt: decrypt}, nil
+}
+
+func (v *vigenere) XORKeyStream(dst, src []byte) {
+  if len(dst) < len(src) {
+    panic("grypto/vigenere: output smaller than input")
+  }
+
+  for i := range src {
+    if !isLatin(src[i]) {
+      dst[i] = src[i]
+      continue
+    }
+
+    if v.decrypt {
+      v.ciphers[v.pos].Decrypt(dst[i:i+1], src[i:i+1])
+    } else {
+      v.ciphers[v.pos].Encrypt(dst[i:i+1], src[i:i+1])
+    }
+    v.pos = (v.pos + 1) % len(v.ciphers)
+  }
+}
+
+func isLatin(b byte) bool {
+  return b >= 'A' && b <= 'Z' || b >= 'a' && b <= 'z'
+}
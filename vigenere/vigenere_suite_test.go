@@ -0,0 +1,13 @@
+package vigenere_test
+
+import (
+  "testing"
+
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+)
+
+func TestVigenere(t *testing.T) {
+  RegisterFailHandler(Fail)
+  RunSpecs(t, "Vigenère Cipher Suite")
+}
@@ -0,0 +1,66 @@
+package vigenere_test
+
+import (
+  . "github.com/onsi/ginkgo"
+  . "github.com/onsi/gomega"
+
+  "github.com/timebertt/grypto/vigenere"
+)
+
+var _ = Describe("Vigenère Cipher", func() {
+  encrypt := func(key, in string) string {
+    s, err := vigenere.NewEncrypter(key)
+    ExpectWithOffset(1, err).NotTo(HaveOccurred())
+    out := make([]byte, len(in))
+    s.XORKeyStream(out, []byte(in))
+    return string(out)
+  }
+
+  decrypt := func(key, in string) string {
+    s, err := vigenere.NewDecrypter(key)
+    ExpectWithOffset(1, err).NotTo(HaveOccurred())
+    out := make([]byte, len(in))
+    s.XORKeyStream(out, []byte(in))
+    return string(out)
+  }
+
+  It("should reject invalid keys", func() {
+    _, err := vigenere.NewEncrypter("")
+    Expect(err).To(HaveOccurred())
+    _, err = vigenere.NewEncrypter("lemon!")
+    Expect(err).To(HaveOccurred())
+    _, err = vigenere.NewDecrypter("le mon")
+    Expect(err).To(HaveOccurred())
+  })
+
+  It("should encrypt upper case letters", func() {
+    Expect(encrypt("LEMON", "ATTACKATDAWN")).To(Equal("LXFOPVEFRNHR"))
+    Expect(decrypt("LEMON", "LXFOPVEFRNHR")).To(Equal("ATTACKATDAWN"))
+  })
+
+  It("should ignore the case of the key", func() {
+    Expect(encrypt("lemon", "ATTACKATDAWN")).To(Equal("LXFOPVEFRNHR"))
+    Expect(encrypt("LeMoN", "ATTACKATDAWN")).To(Equal("LXFOPVEFRNHR"))
+  })
+
+  It("should keep case and not encrypt non-latin letters", func() {
+    Expect(encrypt("LEMON", "Attack at dawn!")).To(Equal("Lxfopv ef rnhr!"))
+    Expect(decrypt("LEMON", "Lxfopv ef rnhr!")).To(Equal("Attack at dawn!"))
+  })
+
+  It("should behave like the Caesar Cipher for single letter keys", func() {
+    Expect(encrypt("D", "Caesar Cipher is old but not very secure")).To(Equal("Fdhvdu Flskhu lv rog exw qrw yhub vhfxuh"))
+  })
+
+  It("should continue at the current key position in subsequent calls", func() {
+    s, err := vigenere.NewEncrypter("LEMON")
+    Expect(err).NotTo(HaveOccurred())
+
+    in := []byte("Attack at dawn!")
+    out := make([]byte, len(in))
+    s.XORKeyStream(out[:4], in[:4])
+    s.XORKeyStream(out[4:9], in[4:9])
+    s.XORKeyStream(out[9:], in[9:])
+    Expect(string(out)).To(Equal("Lxfopv ef rnhr!"))
+  })
+})